@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/tracker"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFrom string
+	diffTo   string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff owner/repo#number --from TIME [--to TIME]",
+	Short: "Show how an item changed between two times, from history kept by sync --history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		owner, repo, number, err := parseItemRef(args[0])
+		if err != nil {
+			return err
+		}
+		if diffFrom == "" {
+			return fmt.Errorf("--from is required")
+		}
+		from, err := parseTime("from", diffFrom)
+		if err != nil {
+			return err
+		}
+		to := time.Now()
+		if diffTo != "" {
+			if to, err = parseTime("to", diffTo); err != nil {
+				return err
+			}
+		}
+		return tracker.PrintItemDiff(os.Stdout, outputDir, owner, repo, number, from, to)
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&outputDir, "output", "o", "out", "Output directory containing synced data")
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "Compare the version current at this time (RFC3339 or YYYY-MM-DD)")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "With the version current at this time (default: latest)")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffCommandFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing from", []string{"diff", "o/r#1"}, "--from is required"},
+		{"invalid from", []string{"diff", "o/r#1", "--from", "yesterday"}, "invalid --from format"},
+		{"invalid to", []string{"diff", "o/r#1", "--from", "2024-01-01", "--to", "later"}, "invalid --to format"},
+		{"invalid reference", []string{"diff", "o/r", "--from", "2024-01-01"}, "invalid reference format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffFrom, diffTo = "", ""
+			rootCmd.SetArgs(append(tt.args, "--output", t.TempDir()))
+			rootCmd.SetOut(io.Discard)
+			rootCmd.SetErr(io.Discard)
+			err := rootCmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		valid bool
+	}{
+		{"2024-01-15T10:30:00Z", "2024-01-15T10:30:00Z", true},
+		{"2024-01-15T12:30:00+02:00", "2024-01-15T10:30:00Z", true},
+		{"2024-01-15", "2024-01-15T00:00:00Z", true},
+		{"2024-01-15 10:30", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, err := parseTime("since", tt.value)
+		if !tt.valid {
+			if err == nil || !strings.Contains(err.Error(), "--since") {
+				t.Errorf("parseTime(%q): got error %v, want one naming --since", tt.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTime(%q): %v", tt.value, err)
+			continue
+		}
+		if s := got.UTC().Format(time.RFC3339); s != tt.want {
+			t.Errorf("parseTime(%q) = %s, want %s", tt.value, s, tt.want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/itaysk/gh-dumpster/internal/export"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat  string
+	exportKinds   []string
+	exportDest    string
+	exportColumns []string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export synced data as flat datasets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := export.Options{
+			InputDir:  outputDir,
+			OutputDir: exportDest,
+			Format:    exportFormat,
+			Columns:   exportColumns,
+		}
+		for _, k := range exportKinds {
+			opts.Kinds = append(opts.Kinds, storage.Kind(k))
+		}
+		if len(opts.Kinds) == 0 {
+			opts.Kinds = []storage.Kind{storage.KindIssue, storage.KindPR, storage.KindDiscussion}
+		}
+		return export.Export(opts)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&outputDir, "output", "o", "out", "Output directory containing synced data")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", export.FormatJSONL, "Export format: jsonl, parquet, csv")
+	exportCmd.Flags().StringSliceVarP(&exportKinds, "kind", "k", nil, "Kinds to export: issue, pr, discussion (default: all)")
+	exportCmd.Flags().StringVarP(&exportDest, "dest", "d", "export", "Directory to write the exported files to")
+	exportCmd.Flags().StringSliceVarP(&exportColumns, "columns", "c", nil, "Columns to write in csv format, e.g. number,title,state (default: per kind)")
+	rootCmd.AddCommand(exportCmd)
+}
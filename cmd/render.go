@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/itaysk/gh-dumpster/internal/render"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renderFormat string
+	renderKinds  []string
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Render synced items as markdown next to their JSON files",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var kinds []storage.Kind
+		for _, k := range renderKinds {
+			kinds = append(kinds, storage.Kind(k))
+		}
+		if len(kinds) == 0 {
+			kinds = []storage.Kind{storage.KindIssue, storage.KindPR, storage.KindDiscussion}
+		}
+		return render.Render(outputDir, renderFormat, kinds)
+	},
+}
+
+func init() {
+	renderCmd.Flags().StringVarP(&outputDir, "output", "o", "out", "Output directory containing synced data")
+	renderCmd.Flags().StringVarP(&renderFormat, "format", "f", render.FormatMarkdown, "Render format: markdown")
+	renderCmd.Flags().StringSliceVarP(&renderKinds, "kind", "k", nil, "Kinds to render: issue, pr, discussion (default: all)")
+	rootCmd.AddCommand(renderCmd)
+}
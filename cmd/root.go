@@ -11,9 +11,16 @@ import (
 )
 
 var (
-	outputDir string
-	kinds     []string
-	sinceStr  string
+	outputDir  string
+	kinds      []string
+	sinceStr   string
+	sqlitePath string
+	gitCommit  bool
+	history    bool
+	changesOut string
+	onChange   string
+	onComplete string
+	hookJobs   int
 )
 
 var rootCmd = &cobra.Command{
@@ -33,26 +40,36 @@ var syncCmd = &cobra.Command{
 		}
 
 		opts := tracker.SyncOptions{
-			Owner:     parts[0],
-			Repo:      parts[1],
-			OutputDir: outputDir,
+			Owner:      parts[0],
+			Repo:       parts[1],
+			OutputDir:  outputDir,
+			SQLitePath: sqlitePath,
+			Git:        gitCommit,
+			History:    history,
+			ChangesOut: changesOut,
+			OnChange:   onChange,
+			OnComplete: onComplete,
+			HookJobs:   hookJobs,
 		}
 
 		if sinceStr != "" {
-			t, err := time.Parse(time.RFC3339, sinceStr)
+			t, err := parseTime("since", sinceStr)
 			if err != nil {
-				t, err = time.Parse("2006-01-02", sinceStr)
-				if err != nil {
-					return fmt.Errorf("invalid --since format, use RFC3339 (2006-01-02T15:04:05Z) or date (2006-01-02)")
-				}
+				return err
 			}
 			opts.Since = &t
 		}
 
+		// Workflow runs are opt-in: each run costs extra REST requests for
+		// its jobs, which would make a default first sync very slow.
+		// Projects are opt-in because they need the read:project scope.
 		if len(kinds) == 0 {
 			opts.Issues = true
 			opts.PRs = true
 			opts.Discussions = true
+			opts.Repository = true
+			opts.Releases = true
+			opts.CommitComments = true
 		} else {
 			for _, k := range kinds {
 				switch k {
@@ -62,8 +79,18 @@ var syncCmd = &cobra.Command{
 					opts.PRs = true
 				case "discussion":
 					opts.Discussions = true
+				case "repo":
+					opts.Repository = true
+				case "release":
+					opts.Releases = true
+				case "project":
+					opts.Projects = true
+				case "workflow-run":
+					opts.WorkflowRuns = true
+				case "commit-comment":
+					opts.CommitComments = true
 				default:
-					return fmt.Errorf("unknown kind: %s (valid: issue, pr, discussion)", k)
+					return fmt.Errorf("unknown kind: %s (valid: issue, pr, discussion, repo, release, project, workflow-run, commit-comment)", k)
 				}
 			}
 		}
@@ -72,10 +99,29 @@ var syncCmd = &cobra.Command{
 	},
 }
 
+// parseTime parses the value of a time flag as RFC3339 or as a date.
+func parseTime(flag, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --%s format, use RFC3339 (2006-01-02T15:04:05Z) or date (2006-01-02)", flag)
+		}
+	}
+	return t, nil
+}
+
 func init() {
 	syncCmd.Flags().StringVarP(&outputDir, "output", "o", "out", "Output directory for JSON files")
-	syncCmd.Flags().StringSliceVarP(&kinds, "kinds", "k", nil, "Resource types to sync: issue, pr, discussion (default: all)")
+	syncCmd.Flags().StringSliceVarP(&kinds, "kinds", "k", nil, "Resource types to sync: issue, pr, discussion, repo, release, project, workflow-run, commit-comment (default: all but project and workflow-run)")
 	syncCmd.Flags().StringVar(&sinceStr, "since", "", "Sync items updated after this time (RFC3339 or YYYY-MM-DD)")
+	syncCmd.Flags().StringVar(&sqlitePath, "sqlite", "", "Also upsert synced items into this SQLite database")
+	syncCmd.Flags().BoolVar(&gitCommit, "git", false, "Keep the output directory in git and commit after every sync")
+	syncCmd.Flags().BoolVar(&history, "history", false, "Keep every version of synced items under .history for the diff command")
+	syncCmd.Flags().StringVar(&changesOut, "changes-out", "", "Also stream the change feed as JSON Lines to this file or named pipe")
+	syncCmd.Flags().StringVar(&onChange, "on-change", "", "Shell command to run for each changed item, with {kind}, {number}, {path} and {action} replaced")
+	syncCmd.Flags().StringVar(&onComplete, "on-complete", "", "Shell command to run once after a sync that changed items, with {run_id}, {changes} and {count} replaced; the changes are on its stdin")
+	syncCmd.Flags().IntVar(&hookJobs, "hook-jobs", tracker.DefaultHookJobs, "Maximum number of --on-change commands running at once")
 	rootCmd.AddCommand(syncCmd)
 }
 
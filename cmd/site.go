@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/itaysk/gh-dumpster/internal/site"
+	"github.com/spf13/cobra"
+)
+
+var siteDest string
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Generate a static HTML archive from synced data",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return site.Generate(outputDir, siteDest)
+	},
+}
+
+func init() {
+	siteCmd.Flags().StringVarP(&outputDir, "output", "o", "out", "Output directory containing synced data")
+	siteCmd.Flags().StringVarP(&siteDest, "dest", "d", "site", "Directory to write the site to")
+	rootCmd.AddCommand(siteCmd)
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/itaysk/gh-dumpster/internal/tracker"
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree owner/repo#number",
+	Short: "Print the sub-issue tree of an issue from local data",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		owner, repo, number, err := parseItemRef(args[0])
+		if err != nil {
+			return err
+		}
+		return tracker.PrintIssueTree(os.Stdout, outputDir, owner, repo, number)
+	},
+}
+
+// parseItemRef splits an owner/repo#number reference.
+func parseItemRef(ref string) (owner, repo string, number int, err error) {
+	repoPart, numPart, ok := strings.Cut(ref, "#")
+	parts := strings.Split(repoPart, "/")
+	if !ok || len(parts) != 2 {
+		return "", "", 0, fmt.Errorf("invalid reference format, expected owner/repo#number")
+	}
+	number, err = strconv.Atoi(numPart)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid issue number: %s", numPart)
+	}
+	return parts[0], parts[1], number, nil
+}
+
+func init() {
+	treeCmd.Flags().StringVarP(&outputDir, "output", "o", "out", "Output directory containing synced data")
+	rootCmd.AddCommand(treeCmd)
+}
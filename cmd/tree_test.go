@@ -0,0 +1,37 @@
+package cmd
+
+import "testing"
+
+func TestParseItemRef(t *testing.T) {
+	tests := []struct {
+		ref    string
+		owner  string
+		repo   string
+		number int
+		valid  bool
+	}{
+		{"octo/repo#12", "octo", "repo", 12, true},
+		{"octo/repo.go#1", "octo", "repo.go", 1, true},
+		{"octo/repo", "", "", 0, false},
+		{"repo#12", "", "", 0, false},
+		{"octo/repo/extra#12", "", "", 0, false},
+		{"octo/repo#", "", "", 0, false},
+		{"octo/repo#twelve", "", "", 0, false},
+	}
+	for _, tt := range tests {
+		owner, repo, number, err := parseItemRef(tt.ref)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("parseItemRef(%q) accepted an invalid reference", tt.ref)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseItemRef(%q): %v", tt.ref, err)
+			continue
+		}
+		if owner != tt.owner || repo != tt.repo || number != tt.number {
+			t.Errorf("parseItemRef(%q) = %s, %s, %d, want %s, %s, %d", tt.ref, owner, repo, number, tt.owner, tt.repo, tt.number)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// defaultColumns are the CSV columns written for a kind when none are
+// selected.
+var defaultColumns = map[storage.Kind][]string{
+	storage.KindIssue:      {"number", "title", "state", "author", "labels", "created_at", "closed_at", "comment_count"},
+	storage.KindPR:         {"number", "title", "state", "author", "labels", "created_at", "merged_at", "closed_at", "review_count"},
+	storage.KindDiscussion: {"number", "title", "category", "author", "is_answered", "upvote_count", "created_at", "closed_at", "comment_count"},
+}
+
+// csvWriter writes the selected columns of rows of one type, addressed by
+// their json names.
+type csvWriter struct {
+	w      *csv.Writer
+	fields []int
+}
+
+// csvFields resolves columns to the indexes of the row fields with those
+// json names.
+func csvFields(model any, columns []string) ([]int, error) {
+	typ := reflect.TypeOf(model)
+	index := map[string]int{}
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		index[name] = i
+		names = append(names, name)
+	}
+
+	var fields []int
+	for _, c := range columns {
+		i, ok := index[c]
+		if !ok {
+			return nil, fmt.Errorf("unknown column: %s (valid: %s)", c, strings.Join(names, ", "))
+		}
+		fields = append(fields, i)
+	}
+	return fields, nil
+}
+
+func newCSVWriter(out io.Writer, columns []string, fields []int) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(out), fields: fields}
+	if err := cw.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(row any) error {
+	v := reflect.ValueOf(row)
+	record := make([]string, len(cw.fields))
+	for i, f := range cw.fields {
+		record[i] = csvValue(v.Field(f).Interface())
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return csvValue(*v)
+	case []string:
+		return strings.Join(v, ", ")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,114 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestCSVFields(t *testing.T) {
+	fields, err := csvFields(IssueRow{}, []string{"title", "number"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typ := reflect.TypeOf(IssueRow{})
+	if typ.Field(fields[0]).Name != "Title" || typ.Field(fields[1]).Name != "Number" {
+		t.Errorf("got fields %v", fields)
+	}
+
+	_, err = csvFields(IssueRow{}, []string{"title", "nope"})
+	if err == nil || !strings.Contains(err.Error(), "unknown column: nope") || !strings.Contains(err.Error(), "comment_count") {
+		t.Errorf("got %v, want an unknown column error listing the valid columns", err)
+	}
+}
+
+func TestCSVValue(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))
+	tests := []struct {
+		in   any
+		want string
+	}{
+		{"text", "text"},
+		{42, "42"},
+		{int64(1) << 40, "1099511627776"},
+		{true, "true"},
+		{at, "2024-01-02T02:04:05Z"},
+		{time.Time{}, ""},
+		{(*time.Time)(nil), ""},
+		{&at, "2024-01-02T02:04:05Z"},
+		{[]string{"a", "b"}, "a, b"},
+		{[]string(nil), ""},
+	}
+	for _, tt := range tests {
+		if got := csvValue(tt.in); got != tt.want {
+			t.Errorf("csvValue(%#v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestExportCSVColumnsForEveryKind(t *testing.T) {
+	in, out := writeTestDump(t), t.TempDir()
+	columns := strings.Split("number,title,state,author,labels,created_at,closed_at,comment_count", ",")
+	if err := Export(Options{InputDir: in, OutputDir: out, Format: FormatCSV, Kinds: allKinds, Columns: columns}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"issues":        {"1", "bug", "CLOSED", "mona", "bug, p1", "2024-01-01T00:00:00Z", "2024-01-01T01:00:00Z", "2"},
+		"pull_requests": {"2", "fix", "MERGED", "", "", "2024-01-01T00:00:00Z", "", "0"},
+		"discussions":   {"3", "question", "CLOSED", "", "", "2024-01-01T00:00:00Z", "", "2"},
+	}
+	for name, row := range want {
+		records := readCSV(t, filepath.Join(out, name+".csv"))
+		if len(records) != 2 || !reflect.DeepEqual(records[0], columns) || !reflect.DeepEqual(records[1], row) {
+			t.Errorf("%s.csv = %q, want header and %q", name, records, row)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "issue_comments.csv")); !os.IsNotExist(err) {
+		t.Errorf("CSV export wrote a child table")
+	}
+}
+
+func TestExportCSVDefaultColumns(t *testing.T) {
+	in, out := writeTestDump(t), t.TempDir()
+	if err := Export(Options{InputDir: in, OutputDir: out, Format: FormatCSV, Kinds: allKinds}); err != nil {
+		t.Fatal(err)
+	}
+	records := readCSV(t, filepath.Join(out, "discussions.csv"))
+	if !reflect.DeepEqual(records[0], defaultColumns[storage.KindDiscussion]) {
+		t.Errorf("got header %q", records[0])
+	}
+}
+
+func TestExportCSVValidatesBeforeWriting(t *testing.T) {
+	in := writeTestDump(t)
+	out := filepath.Join(t.TempDir(), "out")
+	// merged_at exists for pull requests only.
+	err := Export(Options{InputDir: in, OutputDir: out, Format: FormatCSV, Kinds: allKinds, Columns: []string{"number", "merged_at"}})
+	if err == nil || !strings.Contains(err.Error(), "invalid columns for issue") {
+		t.Fatalf("got %v, want an invalid columns error", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("export created %s before failing", out)
+	}
+}
@@ -0,0 +1,305 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+	FormatCSV     = "csv"
+)
+
+type Options struct {
+	InputDir  string
+	OutputDir string
+	Format    string
+	Kinds     []storage.Kind
+
+	// Columns selects the CSV columns by their json names. When empty, each
+	// kind gets its default columns.
+	Columns []string
+}
+
+// table is one exported dataset. The model is the row type written to it,
+// which defines the Parquet schema.
+type table struct {
+	name  string
+	model any
+}
+
+// kindTables lists the datasets written for each exportable kind, the item
+// table first.
+var kindTables = map[storage.Kind][]table{
+	storage.KindIssue: {
+		{"issues", IssueRow{}},
+		{"issue_comments", CommentRow{}},
+		{"issue_events", EventRow{}},
+	},
+	storage.KindPR: {
+		{"pull_requests", PullRequestRow{}},
+		{"pull_request_comments", CommentRow{}},
+		{"pull_request_reviews", ReviewRow{}},
+		{"pull_request_review_comments", ReviewCommentRow{}},
+		{"pull_request_events", EventRow{}},
+	},
+	storage.KindDiscussion: {
+		{"discussions", DiscussionRow{}},
+		{"discussion_comments", CommentRow{}},
+		{"discussion_events", EventRow{}},
+	},
+}
+
+// Export reads the items of each kind back from the JSON tree in InputDir
+// and writes them as flat datasets to OutputDir, one file per table.
+func Export(opts Options) error {
+	switch opts.Format {
+	case FormatJSONL, FormatParquet, FormatCSV:
+	default:
+		return fmt.Errorf("unknown format: %s (valid: %s, %s, %s)", opts.Format, FormatJSONL, FormatParquet, FormatCSV)
+	}
+	if len(opts.Columns) > 0 && opts.Format != FormatCSV {
+		return fmt.Errorf("columns can only be selected for the %s format", FormatCSV)
+	}
+	for _, kind := range opts.Kinds {
+		if _, ok := kindTables[kind]; !ok {
+			return fmt.Errorf("export of %s is not supported", kind)
+		}
+	}
+
+	// Resolve the CSV columns of every kind up front, so that a column
+	// missing from one kind fails the export before any file is written.
+	columns := map[storage.Kind][]string{}
+	fields := map[storage.Kind][]int{}
+	if opts.Format == FormatCSV {
+		for _, kind := range opts.Kinds {
+			columns[kind] = opts.Columns
+			if len(opts.Columns) == 0 {
+				columns[kind] = defaultColumns[kind]
+			}
+			f, err := csvFields(kindTables[kind][0].model, columns[kind])
+			if err != nil {
+				return fmt.Errorf("invalid columns for %s: %w", kind, err)
+			}
+			fields[kind] = f
+		}
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	store := storage.New(opts.InputDir)
+	for _, kind := range opts.Kinds {
+		ds, err := openDataset(opts.OutputDir, opts.Format, kindTables[kind], columns[kind], fields[kind])
+		if err != nil {
+			return err
+		}
+		n, err := exportKind(store, kind, ds)
+		if closeErr := ds.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", kind, err)
+		}
+		fmt.Printf("Exported %d %s items to %s\n", n, kind, opts.OutputDir)
+	}
+	return nil
+}
+
+func exportKind(store storage.Backend, kind storage.Kind, ds *dataset) (int, error) {
+	keys, err := store.List(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		switch kind {
+		case storage.KindIssue:
+			var issue github.Issue
+			if err := store.Load(kind, key, &issue); err != nil {
+				return 0, fmt.Errorf("failed to load issue %d: %w", key, err)
+			}
+			err = writeIssue(ds, &issue)
+		case storage.KindPR:
+			var pr github.PullRequest
+			if err := store.Load(kind, key, &pr); err != nil {
+				return 0, fmt.Errorf("failed to load PR %d: %w", key, err)
+			}
+			err = writePR(ds, &pr)
+		case storage.KindDiscussion:
+			var disc github.Discussion
+			if err := store.Load(kind, key, &disc); err != nil {
+				return 0, fmt.Errorf("failed to load discussion %d: %w", key, err)
+			}
+			err = writeDiscussion(ds, &disc)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+func writeIssue(ds *dataset, issue *github.Issue) error {
+	if err := ds.Write("issues", issueRow(issue)); err != nil {
+		return err
+	}
+	for _, c := range issue.Comments {
+		if err := ds.Write("issue_comments", commentRow(issue.Number, c)); err != nil {
+			return err
+		}
+	}
+	return writeEvents(ds, "issue_events", issue.Number, issue.Events)
+}
+
+func writePR(ds *dataset, pr *github.PullRequest) error {
+	if err := ds.Write("pull_requests", prRow(pr)); err != nil {
+		return err
+	}
+	for _, c := range pr.Comments {
+		if err := ds.Write("pull_request_comments", commentRow(pr.Number, c)); err != nil {
+			return err
+		}
+	}
+	for _, r := range pr.Reviews {
+		if err := ds.Write("pull_request_reviews", reviewRow(pr.Number, r)); err != nil {
+			return err
+		}
+		for _, c := range r.Comments {
+			if err := ds.Write("pull_request_review_comments", reviewCommentRow(pr.Number, r.ID, c)); err != nil {
+				return err
+			}
+		}
+	}
+	return writeEvents(ds, "pull_request_events", pr.Number, pr.Events)
+}
+
+func writeDiscussion(ds *dataset, disc *github.Discussion) error {
+	if err := ds.Write("discussions", discussionRow(disc)); err != nil {
+		return err
+	}
+	for _, c := range disc.Comments {
+		if err := ds.Write("discussion_comments", discussionCommentRow(disc.Number, c)); err != nil {
+			return err
+		}
+		for _, r := range c.Replies {
+			if err := ds.Write("discussion_comments", discussionReplyRow(disc.Number, c.ID, r)); err != nil {
+				return err
+			}
+		}
+	}
+	return writeEvents(ds, "discussion_events", disc.Number, disc.Events)
+}
+
+func writeEvents(ds *dataset, name string, number int, events []github.Event) error {
+	for i, e := range events {
+		row, err := eventRow(number, i, e)
+		if err != nil {
+			return err
+		}
+		if err := ds.Write(name, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dataset holds the open table files of one kind. CSV exports only have
+// the item table open; rows of other tables are dropped.
+type dataset struct {
+	files   []*os.File
+	writers map[string]rowWriter
+}
+
+type rowWriter interface {
+	Write(row any) error
+	Close() error
+}
+
+// openDataset creates the table files of one kind. For CSV, only the item
+// table is created, with the given columns resolved to fields by csvFields.
+func openDataset(dir, format string, tables []table, columns []string, fields []int) (*dataset, error) {
+	if format == FormatCSV {
+		tables = tables[:1]
+	}
+
+	ds := &dataset{writers: map[string]rowWriter{}}
+	for _, t := range tables {
+		f, err := os.Create(filepath.Join(dir, t.name+"."+format))
+		if err != nil {
+			ds.Close()
+			return nil, err
+		}
+		ds.files = append(ds.files, f)
+
+		switch format {
+		case FormatParquet:
+			ds.writers[t.name] = parquet.NewWriter(f, parquet.SchemaOf(t.model))
+		case FormatCSV:
+			w, err := newCSVWriter(f, columns, fields)
+			if err != nil {
+				ds.Close()
+				return nil, err
+			}
+			ds.writers[t.name] = w
+		default:
+			ds.writers[t.name] = newJSONLWriter(f)
+		}
+	}
+	return ds, nil
+}
+
+func (ds *dataset) Write(name string, row any) error {
+	w, ok := ds.writers[name]
+	if !ok {
+		return nil
+	}
+	if err := w.Write(row); err != nil {
+		return fmt.Errorf("failed to write %s row: %w", name, err)
+	}
+	return nil
+}
+
+// Close flushes every table and closes its file, returning the first error.
+func (ds *dataset) Close() error {
+	var firstErr error
+	for _, w := range ds.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, f := range ds.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// jsonlWriter writes one JSON object per line.
+type jsonlWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLWriter(f *os.File) *jsonlWriter {
+	buf := bufio.NewWriter(f)
+	return &jsonlWriter{buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (w *jsonlWriter) Write(row any) error {
+	return w.enc.Encode(row)
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.buf.Flush()
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+	"github.com/parquet-go/parquet-go"
+)
+
+var allKinds = []storage.Kind{storage.KindIssue, storage.KindPR, storage.KindDiscussion}
+
+// writeTestDump saves an issue, a pull request and a discussion with a few
+// children each to a JSON tree in a new directory.
+func writeTestDump(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	store := storage.New(dir)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closed := created.Add(time.Hour)
+
+	items := map[storage.Kind]any{
+		storage.KindIssue: github.Issue{
+			Number: 1, Title: "bug", State: "CLOSED", Author: github.Actor{Login: "mona"},
+			Labels:    []github.Label{{Name: "bug"}, {Name: "p1"}},
+			CreatedAt: created, ClosedAt: &closed,
+			Comments: []github.Comment{{ID: "IC_1"}, {ID: "IC_2"}},
+			Events:   []github.Event{{Type: "labeled", Details: map[string]string{"label": "bug"}}},
+		},
+		storage.KindPR: github.PullRequest{
+			Number: 2, Title: "fix", State: "MERGED", CreatedAt: created,
+			Reviews: []github.Review{{ID: "PRR_1", Comments: []github.ReviewComment{{ID: "PRRC_1"}, {ID: "PRRC_2"}}}},
+		},
+		storage.KindDiscussion: github.Discussion{
+			Number: 3, Title: "question", Category: "Q&A", Closed: true, IsAnswered: true, CreatedAt: created,
+			Comments: []github.DiscussionComment{{ID: "DC_1", Replies: []github.DiscussionCommentReply{{ID: "DC_2"}}}},
+		},
+	}
+	for kind, item := range items {
+		key := map[storage.Kind]int{storage.KindIssue: 1, storage.KindPR: 2, storage.KindDiscussion: 3}[kind]
+		if err := store.Save(kind, key, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func readJSONL(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var rows []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var row map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestExportJSONL(t *testing.T) {
+	in, out := writeTestDump(t), t.TempDir()
+	if err := Export(Options{InputDir: in, OutputDir: out, Format: FormatJSONL, Kinds: allKinds}); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{
+		"issues":                       1,
+		"issue_comments":               2,
+		"issue_events":                 1,
+		"pull_requests":                1,
+		"pull_request_comments":        0,
+		"pull_request_reviews":         1,
+		"pull_request_review_comments": 2,
+		"pull_request_events":          0,
+		"discussions":                  1,
+		"discussion_comments":          2,
+		"discussion_events":            0,
+	}
+	for name, want := range counts {
+		if got := len(readJSONL(t, filepath.Join(out, name+".jsonl"))); got != want {
+			t.Errorf("%s: got %d rows, want %d", name, got, want)
+		}
+	}
+
+	issue := readJSONL(t, filepath.Join(out, "issues.jsonl"))[0]
+	if issue["title"] != "bug" || issue["author"] != "mona" || issue["comment_count"] != float64(2) {
+		t.Errorf("unexpected issue row: %v", issue)
+	}
+	replies := readJSONL(t, filepath.Join(out, "discussion_comments.jsonl"))
+	if replies[1]["reply_to"] != "DC_1" {
+		t.Errorf("reply row = %v, want reply_to DC_1", replies[1])
+	}
+	event := readJSONL(t, filepath.Join(out, "issue_events.jsonl"))[0]
+	if event["type"] != "labeled" {
+		t.Errorf("unexpected event row: %v", event)
+	}
+}
+
+func TestExportParquet(t *testing.T) {
+	in, out := writeTestDump(t), t.TempDir()
+	if err := Export(Options{InputDir: in, OutputDir: out, Format: FormatParquet, Kinds: []storage.Kind{storage.KindIssue}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := parquet.ReadFile[IssueRow](filepath.Join(out, "issues.parquet"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Number != 1 || rows[0].ClosedAt == nil || len(rows[0].Labels) != 2 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestExportRejectsUnsupported(t *testing.T) {
+	in, out := writeTestDump(t), t.TempDir()
+	if err := Export(Options{InputDir: in, OutputDir: out, Format: "xml", Kinds: allKinds}); err == nil {
+		t.Error("accepted an unknown format")
+	}
+	if err := Export(Options{InputDir: in, OutputDir: out, Format: FormatJSONL, Kinds: []storage.Kind{storage.KindRelease}}); err == nil {
+		t.Error("accepted an unsupported kind")
+	}
+}
@@ -0,0 +1,381 @@
+package export
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+)
+
+// The row types below are the flat records written by the exporter. Their
+// json and parquet names are the column names of the exported datasets.
+// Comments, reviews and events of an item are exploded into child tables
+// that point back at the item through Number.
+
+type IssueRow struct {
+	Number            int        `json:"number" parquet:"number"`
+	ID                string     `json:"id" parquet:"id"`
+	DatabaseID        int64      `json:"database_id" parquet:"database_id"`
+	URL               string     `json:"url" parquet:"url"`
+	Title             string     `json:"title" parquet:"title"`
+	Body              string     `json:"body" parquet:"body"`
+	State             string     `json:"state" parquet:"state"`
+	Author            string     `json:"author" parquet:"author"`
+	AuthorType        string     `json:"author_type" parquet:"author_type"`
+	AuthorAssociation string     `json:"author_association" parquet:"author_association"`
+	Labels            []string   `json:"labels" parquet:"labels,list"`
+	Assignees         []string   `json:"assignees" parquet:"assignees,list"`
+	Milestone         string     `json:"milestone" parquet:"milestone"`
+	IssueType         string     `json:"issue_type" parquet:"issue_type"`
+	Parent            string     `json:"parent" parquet:"parent"`
+	CreatedAt         time.Time  `json:"created_at" parquet:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" parquet:"updated_at"`
+	ClosedAt          *time.Time `json:"closed_at" parquet:"closed_at,optional"`
+	CommentCount      int        `json:"comment_count" parquet:"comment_count"`
+	EventCount        int        `json:"event_count" parquet:"event_count"`
+}
+
+type PullRequestRow struct {
+	Number            int        `json:"number" parquet:"number"`
+	ID                string     `json:"id" parquet:"id"`
+	DatabaseID        int64      `json:"database_id" parquet:"database_id"`
+	URL               string     `json:"url" parquet:"url"`
+	Title             string     `json:"title" parquet:"title"`
+	Body              string     `json:"body" parquet:"body"`
+	State             string     `json:"state" parquet:"state"`
+	Author            string     `json:"author" parquet:"author"`
+	AuthorType        string     `json:"author_type" parquet:"author_type"`
+	AuthorAssociation string     `json:"author_association" parquet:"author_association"`
+	Labels            []string   `json:"labels" parquet:"labels,list"`
+	Assignees         []string   `json:"assignees" parquet:"assignees,list"`
+	Milestone         string     `json:"milestone" parquet:"milestone"`
+	CreatedAt         time.Time  `json:"created_at" parquet:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" parquet:"updated_at"`
+	ClosedAt          *time.Time `json:"closed_at" parquet:"closed_at,optional"`
+	MergedAt          *time.Time `json:"merged_at" parquet:"merged_at,optional"`
+	CommentCount      int        `json:"comment_count" parquet:"comment_count"`
+	ReviewCount       int        `json:"review_count" parquet:"review_count"`
+	EventCount        int        `json:"event_count" parquet:"event_count"`
+}
+
+type DiscussionRow struct {
+	Number            int        `json:"number" parquet:"number"`
+	ID                string     `json:"id" parquet:"id"`
+	DatabaseID        int64      `json:"database_id" parquet:"database_id"`
+	URL               string     `json:"url" parquet:"url"`
+	Title             string     `json:"title" parquet:"title"`
+	Body              string     `json:"body" parquet:"body"`
+	Author            string     `json:"author" parquet:"author"`
+	AuthorType        string     `json:"author_type" parquet:"author_type"`
+	AuthorAssociation string     `json:"author_association" parquet:"author_association"`
+	Category          string     `json:"category" parquet:"category"`
+	Labels            []string   `json:"labels" parquet:"labels,list"`
+	State             string     `json:"state" parquet:"state"`
+	Closed            bool       `json:"closed" parquet:"closed"`
+	StateReason       string     `json:"state_reason" parquet:"state_reason"`
+	Locked            bool       `json:"locked" parquet:"locked"`
+	UpvoteCount       int        `json:"upvote_count" parquet:"upvote_count"`
+	IsAnswered        bool       `json:"is_answered" parquet:"is_answered"`
+	CreatedAt         time.Time  `json:"created_at" parquet:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" parquet:"updated_at"`
+	ClosedAt          *time.Time `json:"closed_at" parquet:"closed_at,optional"`
+	CommentCount      int        `json:"comment_count" parquet:"comment_count"`
+	EventCount        int        `json:"event_count" parquet:"event_count"`
+}
+
+// CommentRow is a comment on an issue, pull request or discussion. ReplyTo
+// is set on discussion replies to the ID of the comment they answer.
+type CommentRow struct {
+	Number            int        `json:"number" parquet:"number"`
+	ID                string     `json:"id" parquet:"id"`
+	DatabaseID        int64      `json:"database_id" parquet:"database_id"`
+	URL               string     `json:"url" parquet:"url"`
+	ReplyTo           string     `json:"reply_to" parquet:"reply_to"`
+	Author            string     `json:"author" parquet:"author"`
+	AuthorType        string     `json:"author_type" parquet:"author_type"`
+	AuthorAssociation string     `json:"author_association" parquet:"author_association"`
+	Body              string     `json:"body" parquet:"body"`
+	CreatedAt         time.Time  `json:"created_at" parquet:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" parquet:"updated_at"`
+	LastEditedAt      *time.Time `json:"last_edited_at" parquet:"last_edited_at,optional"`
+	IsMinimized       bool       `json:"is_minimized" parquet:"is_minimized"`
+	MinimizedReason   string     `json:"minimized_reason" parquet:"minimized_reason"`
+	IsAnswer          bool       `json:"is_answer" parquet:"is_answer"`
+	EditCount         int        `json:"edit_count" parquet:"edit_count"`
+}
+
+type ReviewRow struct {
+	Number            int       `json:"number" parquet:"number"`
+	ID                string    `json:"id" parquet:"id"`
+	DatabaseID        int64     `json:"database_id" parquet:"database_id"`
+	URL               string    `json:"url" parquet:"url"`
+	Author            string    `json:"author" parquet:"author"`
+	AuthorType        string    `json:"author_type" parquet:"author_type"`
+	AuthorAssociation string    `json:"author_association" parquet:"author_association"`
+	State             string    `json:"state" parquet:"state"`
+	Body              string    `json:"body" parquet:"body"`
+	SubmittedAt       time.Time `json:"submitted_at" parquet:"submitted_at"`
+	CommentCount      int       `json:"comment_count" parquet:"comment_count"`
+}
+
+type ReviewCommentRow struct {
+	Number            int        `json:"number" parquet:"number"`
+	ReviewID          string     `json:"review_id" parquet:"review_id"`
+	ID                string     `json:"id" parquet:"id"`
+	DatabaseID        int64      `json:"database_id" parquet:"database_id"`
+	URL               string     `json:"url" parquet:"url"`
+	Author            string     `json:"author" parquet:"author"`
+	AuthorType        string     `json:"author_type" parquet:"author_type"`
+	AuthorAssociation string     `json:"author_association" parquet:"author_association"`
+	Path              string     `json:"path" parquet:"path"`
+	Body              string     `json:"body" parquet:"body"`
+	CreatedAt         time.Time  `json:"created_at" parquet:"created_at"`
+	LastEditedAt      *time.Time `json:"last_edited_at" parquet:"last_edited_at,optional"`
+	IsMinimized       bool       `json:"is_minimized" parquet:"is_minimized"`
+	MinimizedReason   string     `json:"minimized_reason" parquet:"minimized_reason"`
+}
+
+// EventRow is a timeline event. Seq is its position in the item's event
+// list, since reconstructed discussion events have no ID. Details holds the
+// event-specific details as a JSON object.
+type EventRow struct {
+	Number    int       `json:"number" parquet:"number"`
+	Seq       int       `json:"seq" parquet:"seq"`
+	ID        string    `json:"id" parquet:"id"`
+	Type      string    `json:"type" parquet:"type"`
+	Actor     string    `json:"actor" parquet:"actor"`
+	ActorType string    `json:"actor_type" parquet:"actor_type"`
+	CreatedAt time.Time `json:"created_at" parquet:"created_at"`
+	Details   string    `json:"details" parquet:"details"`
+}
+
+func issueRow(issue *github.Issue) IssueRow {
+	row := IssueRow{
+		Number:            issue.Number,
+		ID:                issue.ID,
+		DatabaseID:        issue.DatabaseID,
+		URL:               issue.URL,
+		Title:             issue.Title,
+		Body:              issue.Body,
+		State:             issue.State,
+		Author:            issue.Author.Login,
+		AuthorType:        issue.Author.Type,
+		AuthorAssociation: issue.AuthorAssociation,
+		Labels:            labelNames(issue.Labels),
+		Assignees:         logins(issue.Assignees),
+		CreatedAt:         issue.CreatedAt,
+		UpdatedAt:         issue.UpdatedAt,
+		ClosedAt:          issue.ClosedAt,
+		CommentCount:      len(issue.Comments),
+		EventCount:        len(issue.Events),
+	}
+	if issue.Milestone != nil {
+		row.Milestone = issue.Milestone.Title
+	}
+	if issue.IssueType != nil {
+		row.IssueType = issue.IssueType.Name
+	}
+	if issue.Parent != nil {
+		row.Parent = issue.Parent.URL
+	}
+	return row
+}
+
+func prRow(pr *github.PullRequest) PullRequestRow {
+	row := PullRequestRow{
+		Number:            pr.Number,
+		ID:                pr.ID,
+		DatabaseID:        pr.DatabaseID,
+		URL:               pr.URL,
+		Title:             pr.Title,
+		Body:              pr.Body,
+		State:             pr.State,
+		Author:            pr.Author.Login,
+		AuthorType:        pr.Author.Type,
+		AuthorAssociation: pr.AuthorAssociation,
+		Labels:            labelNames(pr.Labels),
+		Assignees:         logins(pr.Assignees),
+		CreatedAt:         pr.CreatedAt,
+		UpdatedAt:         pr.UpdatedAt,
+		ClosedAt:          pr.ClosedAt,
+		MergedAt:          pr.MergedAt,
+		CommentCount:      len(pr.Comments),
+		ReviewCount:       len(pr.Reviews),
+		EventCount:        len(pr.Events),
+	}
+	if pr.Milestone != nil {
+		row.Milestone = pr.Milestone.Title
+	}
+	return row
+}
+
+func discussionRow(disc *github.Discussion) DiscussionRow {
+	comments := 0
+	for _, c := range disc.Comments {
+		comments += 1 + len(c.Replies)
+	}
+	return DiscussionRow{
+		Number:            disc.Number,
+		ID:                disc.ID,
+		DatabaseID:        disc.DatabaseID,
+		URL:               disc.URL,
+		Title:             disc.Title,
+		Body:              disc.Body,
+		Author:            disc.Author.Login,
+		AuthorType:        disc.Author.Type,
+		AuthorAssociation: disc.AuthorAssociation,
+		Category:          disc.Category,
+		Labels:            labelNames(disc.Labels),
+		State:             discussionState(disc),
+		Closed:            disc.Closed,
+		StateReason:       disc.StateReason,
+		Locked:            disc.Locked,
+		UpvoteCount:       disc.UpvoteCount,
+		IsAnswered:        disc.IsAnswered,
+		CreatedAt:         disc.CreatedAt,
+		UpdatedAt:         disc.UpdatedAt,
+		ClosedAt:          disc.ClosedAt,
+		CommentCount:      comments,
+		EventCount:        len(disc.Events),
+	}
+}
+
+// discussionState returns OPEN or CLOSED, matching the state values of
+// issues and pull requests.
+func discussionState(disc *github.Discussion) string {
+	if disc.Closed {
+		return "CLOSED"
+	}
+	return "OPEN"
+}
+
+func commentRow(number int, c github.Comment) CommentRow {
+	return CommentRow{
+		Number:            number,
+		ID:                c.ID,
+		DatabaseID:        c.DatabaseID,
+		URL:               c.URL,
+		Author:            c.Author.Login,
+		AuthorType:        c.Author.Type,
+		AuthorAssociation: c.AuthorAssociation,
+		Body:              c.Body,
+		CreatedAt:         c.CreatedAt,
+		UpdatedAt:         c.UpdatedAt,
+		LastEditedAt:      c.LastEditedAt,
+		IsMinimized:       c.IsMinimized,
+		MinimizedReason:   c.MinimizedReason,
+		EditCount:         len(c.Edits),
+	}
+}
+
+func discussionCommentRow(number int, c github.DiscussionComment) CommentRow {
+	return CommentRow{
+		Number:            number,
+		ID:                c.ID,
+		DatabaseID:        c.DatabaseID,
+		URL:               c.URL,
+		Author:            c.Author.Login,
+		AuthorType:        c.Author.Type,
+		AuthorAssociation: c.AuthorAssociation,
+		Body:              c.Body,
+		CreatedAt:         c.CreatedAt,
+		UpdatedAt:         c.UpdatedAt,
+		LastEditedAt:      c.LastEditedAt,
+		IsMinimized:       c.IsMinimized,
+		MinimizedReason:   c.MinimizedReason,
+		IsAnswer:          c.IsAnswer,
+		EditCount:         len(c.Edits),
+	}
+}
+
+func discussionReplyRow(number int, replyTo string, r github.DiscussionCommentReply) CommentRow {
+	return CommentRow{
+		Number:            number,
+		ID:                r.ID,
+		DatabaseID:        r.DatabaseID,
+		URL:               r.URL,
+		ReplyTo:           replyTo,
+		Author:            r.Author.Login,
+		AuthorType:        r.Author.Type,
+		AuthorAssociation: r.AuthorAssociation,
+		Body:              r.Body,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+		LastEditedAt:      r.LastEditedAt,
+		IsMinimized:       r.IsMinimized,
+		MinimizedReason:   r.MinimizedReason,
+		IsAnswer:          r.IsAnswer,
+		EditCount:         len(r.Edits),
+	}
+}
+
+func reviewRow(number int, r github.Review) ReviewRow {
+	return ReviewRow{
+		Number:            number,
+		ID:                r.ID,
+		DatabaseID:        r.DatabaseID,
+		URL:               r.URL,
+		Author:            r.Author.Login,
+		AuthorType:        r.Author.Type,
+		AuthorAssociation: r.AuthorAssociation,
+		State:             r.State,
+		Body:              r.Body,
+		SubmittedAt:       r.SubmittedAt,
+		CommentCount:      len(r.Comments),
+	}
+}
+
+func reviewCommentRow(number int, reviewID string, c github.ReviewComment) ReviewCommentRow {
+	return ReviewCommentRow{
+		Number:            number,
+		ReviewID:          reviewID,
+		ID:                c.ID,
+		DatabaseID:        c.DatabaseID,
+		URL:               c.URL,
+		Author:            c.Author.Login,
+		AuthorType:        c.Author.Type,
+		AuthorAssociation: c.AuthorAssociation,
+		Path:              c.Path,
+		Body:              c.Body,
+		CreatedAt:         c.CreatedAt,
+		LastEditedAt:      c.LastEditedAt,
+		IsMinimized:       c.IsMinimized,
+		MinimizedReason:   c.MinimizedReason,
+	}
+}
+
+func eventRow(number, seq int, e github.Event) (EventRow, error) {
+	row := EventRow{
+		Number:    number,
+		Seq:       seq,
+		ID:        e.ID,
+		Type:      e.Type,
+		Actor:     e.Actor.Login,
+		ActorType: e.Actor.Type,
+		CreatedAt: e.CreatedAt,
+	}
+	if e.Details != nil {
+		data, err := json.Marshal(e.Details)
+		if err != nil {
+			return EventRow{}, err
+		}
+		row.Details = string(data)
+	}
+	return row, nil
+}
+
+func labelNames(labels []github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+func logins(actors []github.Actor) []string {
+	names := make([]string, 0, len(actors))
+	for _, a := range actors {
+		names = append(names, a.Login)
+	}
+	return names
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/shurcooL/githubv4"
@@ -10,7 +11,9 @@ import (
 )
 
 type Client struct {
-	gql *githubv4.Client
+	gql     *githubv4.Client
+	http    *http.Client
+	restURL string
 }
 
 func NewClient() (*Client, error) {
@@ -23,5 +26,5 @@ func NewClient() (*Client, error) {
 	httpClient := oauth2.NewClient(context.Background(), src)
 	gql := githubv4.NewClient(httpClient)
 
-	return &Client{gql: gql}, nil
+	return &Client{gql: gql, http: httpClient, restURL: "https://api.github.com"}, nil
 }
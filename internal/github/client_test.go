@@ -0,0 +1,22 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// newTestClient returns a Client whose GraphQL and REST requests are served
+// by handler.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Client{
+		gql:     githubv4.NewEnterpriseClient(srv.URL+"/graphql", srv.Client()),
+		http:    srv.Client(),
+		restURL: srv.URL,
+	}
+}
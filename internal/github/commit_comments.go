@@ -0,0 +1,118 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// CommitComment is a comment left on a commit, either on the commit as a
+// whole or on a line of one of its files (Path and Position).
+type CommitComment struct {
+	Comment
+	Path     string  `json:"path,omitempty"`
+	Position *int    `json:"position,omitempty"`
+	Commit   *Commit `json:"commit,omitempty"`
+}
+
+type Commit struct {
+	SHA           string    `json:"sha"`
+	URL           string    `json:"url"`
+	Message       string    `json:"message"`
+	AuthorName    string    `json:"author_name"`
+	AuthorEmail   string    `json:"author_email"`
+	AuthoredDate  time.Time `json:"authored_date"`
+	CommittedDate time.Time `json:"committed_date"`
+}
+
+type commitCommentQuery struct {
+	Repository struct {
+		CommitComments struct {
+			PageInfo pageInfo
+			Nodes    []struct {
+				commentNode
+				DatabaseID *int64
+				Path       *githubv4.String
+				Position   *githubv4.Int
+				Commit     *struct {
+					Oid     githubv4.String
+					URL     githubv4.String
+					Message githubv4.String
+					Author  *struct {
+						Name  githubv4.String
+						Email githubv4.String
+					}
+					AuthoredDate  githubv4.DateTime
+					CommittedDate githubv4.DateTime
+				}
+			}
+		} `graphql:"commitComments(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+// FetchCommitComments returns the repository's commit comments updated
+// since the given time. The connection cannot be ordered or filtered, so
+// every page is read and older comments are skipped.
+func (c *Client) FetchCommitComments(ctx context.Context, owner, repo string, since *time.Time) ([]CommitComment, error) {
+	var allComments []CommitComment
+	var cursor *githubv4.String
+
+	for {
+		var q commitCommentQuery
+		vars := map[string]any{
+			"owner":  githubv4.String(owner),
+			"repo":   githubv4.String(repo),
+			"cursor": cursor,
+		}
+
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Repository.CommitComments.Nodes {
+			if since != nil && node.UpdatedAt.Time.Before(*since) {
+				continue
+			}
+
+			base, err := c.fetchComment(ctx, node.commentNode)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch edits of commit comment %s: %w", node.ID, err)
+			}
+			comment := CommitComment{Comment: base}
+			if node.DatabaseID != nil {
+				comment.DatabaseID = *node.DatabaseID
+			}
+			if node.Path != nil {
+				comment.Path = string(*node.Path)
+			}
+			if node.Position != nil {
+				p := int(*node.Position)
+				comment.Position = &p
+			}
+			if node.Commit != nil {
+				comment.Commit = &Commit{
+					SHA:           string(node.Commit.Oid),
+					URL:           string(node.Commit.URL),
+					Message:       string(node.Commit.Message),
+					AuthoredDate:  node.Commit.AuthoredDate.Time,
+					CommittedDate: node.Commit.CommittedDate.Time,
+				}
+				if node.Commit.Author != nil {
+					comment.Commit.AuthorName = string(node.Commit.Author.Name)
+					comment.Commit.AuthorEmail = string(node.Commit.Author.Email)
+				}
+			}
+
+			allComments = append(allComments, comment)
+		}
+
+		if !q.Repository.CommitComments.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &q.Repository.CommitComments.PageInfo.EndCursor
+	}
+
+	return allComments, nil
+}
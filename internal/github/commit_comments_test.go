@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFetchCommitComments(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Variables["cursor"] == nil {
+			fmt.Fprint(w, `{"data":{"repository":{"commitComments":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[{"id":"CC_old","updatedAt":"2023-01-01T00:00:00Z"}]
+			}}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"repository":{"commitComments":{
+			"pageInfo":{"hasNextPage":false},
+			"nodes":[{
+				"id":"CC_1","databaseId":99,"body":"nit","updatedAt":"2024-06-01T00:00:00Z",
+				"author":{"__typename":"User","login":"mona"},
+				"path":"main.go","position":4,
+				"commit":{"oid":"abc123","message":"Fix","author":{"name":"Mona","email":"mona@example.com"},
+					"authoredDate":"2024-05-01T00:00:00Z","committedDate":"2024-05-02T00:00:00Z"}
+			},{
+				"id":"CC_2","updatedAt":"2024-06-02T00:00:00Z","commit":null
+			}]
+		}}}}`)
+	}))
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	comments, err := c.FetchCommitComments(context.Background(), "o", "r", &since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("got %d comments, want 2 updated since %s", len(comments), since)
+	}
+	cc := comments[0]
+	if cc.ID != "CC_1" || cc.DatabaseID != 99 || cc.Body != "nit" || cc.Author.Login != "mona" {
+		t.Errorf("unexpected comment: %+v", cc.Comment)
+	}
+	if cc.Path != "main.go" || cc.Position == nil || *cc.Position != 4 {
+		t.Errorf("Path = %q, Position = %v", cc.Path, cc.Position)
+	}
+	if cc.Commit == nil || cc.Commit.SHA != "abc123" || cc.Commit.AuthorEmail != "mona@example.com" || cc.Commit.CommittedDate.Day() != 2 {
+		t.Errorf("unexpected commit: %+v", cc.Commit)
+	}
+	if comments[1].Commit != nil || comments[1].Position != nil {
+		t.Errorf("unexpected comment without a commit: %+v", comments[1])
+	}
+}
@@ -2,35 +2,92 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 )
 
 type Discussion struct {
-	Number    int                 `json:"number"`
-	Title     string              `json:"title"`
-	Body      string              `json:"body"`
-	Author    Actor               `json:"author"`
-	Category  string              `json:"category"`
-	CreatedAt time.Time           `json:"created_at"`
-	UpdatedAt time.Time           `json:"updated_at"`
-	Comments  []DiscussionComment `json:"comments"`
+	ID                string              `json:"id"`
+	DatabaseID        int64               `json:"database_id,omitempty"`
+	URL               string              `json:"url"`
+	Number            int                 `json:"number"`
+	Title             string              `json:"title"`
+	Body              string              `json:"body"`
+	Author            Actor               `json:"author"`
+	AuthorAssociation string              `json:"author_association"`
+	Category          string              `json:"category"`
+	Labels            []Label             `json:"labels"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+	Closed            bool                `json:"closed"`
+	ClosedAt          *time.Time          `json:"closed_at,omitempty"`
+	StateReason       string              `json:"state_reason,omitempty"`
+	Locked            bool                `json:"locked"`
+	Pinned            bool                `json:"pinned"`
+	UpvoteCount       int                 `json:"upvote_count"`
+	IsAnswered        bool                `json:"is_answered"`
+	Answer            *DiscussionAnswer   `json:"answer,omitempty"`
+	Poll              *Poll               `json:"poll,omitempty"`
+	Comments          []DiscussionComment `json:"comments"`
+	Events            []Event             `json:"events"`
+	Edits             []Edit              `json:"edits,omitempty"`
+}
+
+// DiscussionAnswer identifies the comment chosen as the answer and who
+// chose it.
+type DiscussionAnswer struct {
+	CommentID  string     `json:"comment_id"`
+	CommentURL string     `json:"comment_url"`
+	ChosenAt   *time.Time `json:"chosen_at,omitempty"`
+	ChosenBy   *Actor     `json:"chosen_by,omitempty"`
+}
+
+type Poll struct {
+	Question       string       `json:"question"`
+	TotalVoteCount int          `json:"total_vote_count"`
+	Options        []PollOption `json:"options"`
+}
+
+type PollOption struct {
+	Option         string `json:"option"`
+	TotalVoteCount int    `json:"total_vote_count"`
 }
 
 type DiscussionComment struct {
-	Author    Actor                    `json:"author"`
-	Body      string                   `json:"body"`
-	CreatedAt time.Time                `json:"created_at"`
-	UpdatedAt time.Time                `json:"updated_at"`
-	Replies   []DiscussionCommentReply `json:"replies,omitempty"`
+	ID                string                   `json:"id"`
+	DatabaseID        int64                    `json:"database_id,omitempty"`
+	URL               string                   `json:"url"`
+	Author            Actor                    `json:"author"`
+	AuthorAssociation string                   `json:"author_association"`
+	Body              string                   `json:"body"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+	LastEditedAt      *time.Time               `json:"last_edited_at,omitempty"`
+	IsMinimized       bool                     `json:"is_minimized"`
+	MinimizedReason   string                   `json:"minimized_reason,omitempty"`
+	IsAnswer          bool                     `json:"is_answer"`
+	UpvoteCount       int                      `json:"upvote_count"`
+	Edits             []Edit                   `json:"edits,omitempty"`
+	Replies           []DiscussionCommentReply `json:"replies,omitempty"`
 }
 
 type DiscussionCommentReply struct {
-	Author    Actor     `json:"author"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                string     `json:"id"`
+	DatabaseID        int64      `json:"database_id,omitempty"`
+	URL               string     `json:"url"`
+	Author            Actor      `json:"author"`
+	AuthorAssociation string     `json:"author_association"`
+	Body              string     `json:"body"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	LastEditedAt      *time.Time `json:"last_edited_at,omitempty"`
+	IsMinimized       bool       `json:"is_minimized"`
+	MinimizedReason   string     `json:"minimized_reason,omitempty"`
+	IsAnswer          bool       `json:"is_answer"`
+	UpvoteCount       int        `json:"upvote_count"`
+	Edits             []Edit     `json:"edits,omitempty"`
 }
 
 type discussionQuery struct {
@@ -41,46 +98,128 @@ type discussionQuery struct {
 				EndCursor   githubv4.String
 			}
 			Nodes []struct {
-				Number    githubv4.Int
-				Title     githubv4.String
-				Body      githubv4.String
-				CreatedAt githubv4.DateTime
-				UpdatedAt githubv4.DateTime
-				Author    struct {
-					Login githubv4.String
-				}
-				Category struct {
+				ID                githubv4.String
+				DatabaseID        *int64
+				URL               githubv4.String
+				Number            githubv4.Int
+				Title             githubv4.String
+				Body              githubv4.String
+				CreatedAt         githubv4.DateTime
+				UpdatedAt         githubv4.DateTime
+				Author            *actorNode
+				AuthorAssociation githubv4.String
+				Category          struct {
 					Name githubv4.String
 				}
-				Comments struct {
-					Nodes []struct {
-						Author struct {
-							Login githubv4.String
+				Labels struct {
+					Nodes []labelNode
+				} `graphql:"labels(first: 50)"`
+				Closed         githubv4.Boolean
+				ClosedAt       *githubv4.DateTime
+				StateReason    *githubv4.String
+				Locked         githubv4.Boolean
+				UpvoteCount    githubv4.Int
+				IsAnswered     *githubv4.Boolean
+				AnswerChosenAt *githubv4.DateTime
+				AnswerChosenBy *actorNode
+				Answer         *struct {
+					ID  githubv4.String
+					URL githubv4.String
+				}
+				Poll *struct {
+					Question       githubv4.String
+					TotalVoteCount githubv4.Int
+					Options        struct {
+						Nodes []struct {
+							Option         githubv4.String
+							TotalVoteCount githubv4.Int
 						}
-						Body      githubv4.String
-						CreatedAt githubv4.DateTime
-						UpdatedAt githubv4.DateTime
-						Replies   struct {
-							Nodes []struct {
-								Author struct {
-									Login githubv4.String
-								}
-								Body      githubv4.String
-								CreatedAt githubv4.DateTime
-								UpdatedAt githubv4.DateTime
-							}
-						} `graphql:"replies(first: 20)"`
-					}
-				} `graphql:"comments(first: 50)"`
+					} `graphql:"options(first: 20)"`
+				}
+				UserContentEdits userContentEdits            `graphql:"userContentEdits(first: 20)"`
+				Comments         discussionCommentConnection `graphql:"comments(first: 50)"`
 			}
 		} `graphql:"discussions(first: 10, orderBy: {field: UPDATED_AT, direction: DESC}, after: $cursor)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
 }
 
+type pinnedDiscussionsQuery struct {
+	Repository struct {
+		PinnedDiscussions struct {
+			Nodes []pinnedDiscussion
+		} `graphql:"pinnedDiscussions(first: 100)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type pinnedDiscussion struct {
+	Discussion struct {
+		Number githubv4.Int
+	}
+	PinnedBy  *actorNode
+	CreatedAt githubv4.DateTime
+}
+
+type discussionCommentNode struct {
+	commentNode
+	DatabaseID  *int64
+	IsAnswer    githubv4.Boolean
+	UpvoteCount githubv4.Int
+}
+
+type discussionCommentConnection struct {
+	PageInfo pageInfo
+	Nodes    []struct {
+		discussionCommentNode
+		Replies discussionReplyConnection `graphql:"replies(first: 20)"`
+	}
+}
+
+type discussionReplyConnection struct {
+	PageInfo pageInfo
+	Nodes    []discussionCommentNode
+}
+
+type pageInfo struct {
+	HasNextPage bool
+	EndCursor   githubv4.String
+}
+
+// discussionCommentsQuery fetches the comments of a discussion past the
+// first page returned by discussionQuery.
+type discussionCommentsQuery struct {
+	Node struct {
+		Discussion struct {
+			Comments discussionCommentConnection `graphql:"comments(first: 50, after: $cursor)"`
+		} `graphql:"... on Discussion"`
+	} `graphql:"node(id: $id)"`
+}
+
+// discussionRepliesQuery fetches the replies of a discussion comment past
+// the first page returned alongside the comment.
+type discussionRepliesQuery struct {
+	Node struct {
+		DiscussionComment struct {
+			Replies discussionReplyConnection `graphql:"replies(first: 50, after: $cursor)"`
+		} `graphql:"... on DiscussionComment"`
+	} `graphql:"node(id: $id)"`
+}
+
 func (c *Client) FetchDiscussions(ctx context.Context, owner, repo string, since *time.Time) ([]Discussion, error) {
 	var allDiscussions []Discussion
 	var cursor *githubv4.String
 
+	var pq pinnedDiscussionsQuery
+	if err := c.gql.Query(ctx, &pq, map[string]any{
+		"owner": githubv4.String(owner),
+		"repo":  githubv4.String(repo),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to fetch pinned discussions: %w", err)
+	}
+	pins := make(map[int]pinnedDiscussion)
+	for _, p := range pq.Repository.PinnedDiscussions.Nodes {
+		pins[int(p.Discussion.Number)] = p
+	}
+
 	for {
 		var q discussionQuery
 		vars := map[string]any{
@@ -102,34 +241,81 @@ func (c *Client) FetchDiscussions(ctx context.Context, owner, repo string, since
 			}
 
 			disc := Discussion{
-				Number:    int(node.Number),
-				Title:     string(node.Title),
-				Body:      string(node.Body),
-				Author:    Actor{Login: string(node.Author.Login)},
-				Category:  string(node.Category.Name),
-				CreatedAt: node.CreatedAt.Time,
-				UpdatedAt: node.UpdatedAt.Time,
-			}
-
-			for _, c := range node.Comments.Nodes {
-				comment := DiscussionComment{
-					Author:    Actor{Login: string(c.Author.Login)},
-					Body:      string(c.Body),
-					CreatedAt: c.CreatedAt.Time,
-					UpdatedAt: c.UpdatedAt.Time,
+				ID:                string(node.ID),
+				URL:               string(node.URL),
+				Number:            int(node.Number),
+				Title:             string(node.Title),
+				Body:              string(node.Body),
+				Author:            convertActor(node.Author),
+				AuthorAssociation: string(node.AuthorAssociation),
+				Category:          string(node.Category.Name),
+				CreatedAt:         node.CreatedAt.Time,
+				UpdatedAt:         node.UpdatedAt.Time,
+				Closed:            bool(node.Closed),
+				Locked:            bool(node.Locked),
+				UpvoteCount:       int(node.UpvoteCount),
+			}
+			if node.DatabaseID != nil {
+				disc.DatabaseID = *node.DatabaseID
+			}
+			if node.ClosedAt != nil {
+				t := node.ClosedAt.Time
+				disc.ClosedAt = &t
+			}
+			if node.StateReason != nil {
+				disc.StateReason = string(*node.StateReason)
+			}
+			if node.IsAnswered != nil {
+				disc.IsAnswered = bool(*node.IsAnswered)
+			}
+
+			for _, l := range node.Labels.Nodes {
+				disc.Labels = append(disc.Labels, convertLabel(l))
+			}
+
+			if node.Answer != nil {
+				disc.Answer = &DiscussionAnswer{
+					CommentID:  string(node.Answer.ID),
+					CommentURL: string(node.Answer.URL),
+				}
+				if node.AnswerChosenAt != nil {
+					t := node.AnswerChosenAt.Time
+					disc.Answer.ChosenAt = &t
 				}
+				if node.AnswerChosenBy != nil {
+					chosenBy := convertActor(node.AnswerChosenBy)
+					disc.Answer.ChosenBy = &chosenBy
+				}
+			}
 
-				for _, r := range c.Replies.Nodes {
-					comment.Replies = append(comment.Replies, DiscussionCommentReply{
-						Author:    Actor{Login: string(r.Author.Login)},
-						Body:      string(r.Body),
-						CreatedAt: r.CreatedAt.Time,
-						UpdatedAt: r.UpdatedAt.Time,
+			if node.Poll != nil {
+				disc.Poll = &Poll{
+					Question:       string(node.Poll.Question),
+					TotalVoteCount: int(node.Poll.TotalVoteCount),
+				}
+				for _, o := range node.Poll.Options.Nodes {
+					disc.Poll.Options = append(disc.Poll.Options, PollOption{
+						Option:         string(o.Option),
+						TotalVoteCount: int(o.TotalVoteCount),
 					})
 				}
+			}
+
+			edits, err := c.collectEdits(ctx, node.ID, node.UserContentEdits)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch edits of discussion %d: %w", disc.Number, err)
+			}
+			disc.Edits = edits
 
-				disc.Comments = append(disc.Comments, comment)
+			comments, err := c.collectDiscussionComments(ctx, node.ID, node.Comments)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch comments of discussion %d: %w", disc.Number, err)
 			}
+			disc.Comments = comments
+
+			pin, pinned := pins[disc.Number]
+			disc.Pinned = pinned
+			disc.Events = discussionEvents(disc, pin)
 
 			allDiscussions = append(allDiscussions, disc)
 		}
@@ -142,3 +328,146 @@ func (c *Client) FetchDiscussions(ctx context.Context, owner, repo string, since
 
 	return allDiscussions, nil
 }
+
+// discussionEvents reconstructs the events that can be dated from the
+// discussion's own fields, since discussions have no timeline. Changes that
+// leave no such trace are recorded by the tracker between syncs.
+func discussionEvents(disc Discussion, pin pinnedDiscussion) []Event {
+	var events []Event
+	if disc.Pinned {
+		events = append(events, Event{
+			Type:      "pinned",
+			Actor:     convertActor(pin.PinnedBy),
+			CreatedAt: pin.CreatedAt.Time,
+		})
+	}
+	if disc.Answer != nil && disc.Answer.ChosenAt != nil {
+		event := Event{
+			Type:      "answer_marked",
+			CreatedAt: *disc.Answer.ChosenAt,
+			Details:   map[string]string{"comment_id": disc.Answer.CommentID},
+		}
+		if disc.Answer.ChosenBy != nil {
+			event.Actor = *disc.Answer.ChosenBy
+		}
+		events = append(events, event)
+	}
+	if disc.Closed && disc.ClosedAt != nil {
+		event := Event{
+			Type:      "closed",
+			CreatedAt: *disc.ClosedAt,
+		}
+		if disc.StateReason != "" {
+			event.Details = map[string]string{"state_reason": disc.StateReason}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// collectDiscussionComments converts the first page of comments and follows
+// the comment and reply cursors until every page has been fetched.
+func (c *Client) collectDiscussionComments(ctx context.Context, discussionID githubv4.String, conn discussionCommentConnection) ([]DiscussionComment, error) {
+	var comments []DiscussionComment
+	for {
+		for _, n := range conn.Nodes {
+			comment := convertDiscussionComment(n.discussionCommentNode)
+			edits, err := c.collectEdits(ctx, n.ID, n.UserContentEdits)
+			if err != nil {
+				return nil, err
+			}
+			comment.Edits = edits
+			replies, err := c.collectDiscussionReplies(ctx, n.ID, n.Replies)
+			if err != nil {
+				return nil, err
+			}
+			comment.Replies = replies
+			comments = append(comments, comment)
+		}
+
+		if !conn.PageInfo.HasNextPage {
+			return comments, nil
+		}
+		var q discussionCommentsQuery
+		vars := map[string]any{
+			"id":     githubv4.ID(string(discussionID)),
+			"cursor": conn.PageInfo.EndCursor,
+		}
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+		conn = q.Node.Discussion.Comments
+	}
+}
+
+func (c *Client) collectDiscussionReplies(ctx context.Context, commentID githubv4.String, conn discussionReplyConnection) ([]DiscussionCommentReply, error) {
+	var replies []DiscussionCommentReply
+	for {
+		for _, n := range conn.Nodes {
+			reply := convertDiscussionReply(n)
+			edits, err := c.collectEdits(ctx, n.ID, n.UserContentEdits)
+			if err != nil {
+				return nil, err
+			}
+			reply.Edits = edits
+			replies = append(replies, reply)
+		}
+
+		if !conn.PageInfo.HasNextPage {
+			return replies, nil
+		}
+		var q discussionRepliesQuery
+		vars := map[string]any{
+			"id":     githubv4.ID(string(commentID)),
+			"cursor": conn.PageInfo.EndCursor,
+		}
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+		conn = q.Node.DiscussionComment.Replies
+	}
+}
+
+func convertDiscussionComment(n discussionCommentNode) DiscussionComment {
+	base := convertComment(n.commentNode)
+	comment := DiscussionComment{
+		ID:                base.ID,
+		URL:               base.URL,
+		Author:            base.Author,
+		AuthorAssociation: base.AuthorAssociation,
+		Body:              base.Body,
+		CreatedAt:         base.CreatedAt,
+		UpdatedAt:         base.UpdatedAt,
+		LastEditedAt:      base.LastEditedAt,
+		IsMinimized:       base.IsMinimized,
+		MinimizedReason:   base.MinimizedReason,
+		IsAnswer:          bool(n.IsAnswer),
+		UpvoteCount:       int(n.UpvoteCount),
+	}
+	if n.DatabaseID != nil {
+		comment.DatabaseID = *n.DatabaseID
+	}
+	return comment
+}
+
+func convertDiscussionReply(n discussionCommentNode) DiscussionCommentReply {
+	base := convertComment(n.commentNode)
+	reply := DiscussionCommentReply{
+		ID:                base.ID,
+		URL:               base.URL,
+		Author:            base.Author,
+		AuthorAssociation: base.AuthorAssociation,
+		Body:              base.Body,
+		CreatedAt:         base.CreatedAt,
+		UpdatedAt:         base.UpdatedAt,
+		LastEditedAt:      base.LastEditedAt,
+		IsMinimized:       base.IsMinimized,
+		MinimizedReason:   base.MinimizedReason,
+		IsAnswer:          bool(n.IsAnswer),
+		UpvoteCount:       int(n.UpvoteCount),
+	}
+	if n.DatabaseID != nil {
+		reply.DatabaseID = *n.DatabaseID
+	}
+	return reply
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConvertDiscussionComment(t *testing.T) {
+	id := int64(17)
+	n := discussionCommentNode{
+		commentNode: commentNode{ID: "DC_1", Body: "the answer", Author: &actorNode{TypeName: "User", Login: "mona"}},
+		DatabaseID:  &id,
+		IsAnswer:    true,
+		UpvoteCount: 4,
+	}
+
+	comment := convertDiscussionComment(n)
+	if comment.ID != "DC_1" || comment.DatabaseID != 17 || comment.Body != "the answer" || comment.Author.Login != "mona" {
+		t.Errorf("unexpected comment: %+v", comment)
+	}
+	if !comment.IsAnswer || comment.UpvoteCount != 4 {
+		t.Errorf("IsAnswer = %v, UpvoteCount = %d, want true, 4", comment.IsAnswer, comment.UpvoteCount)
+	}
+
+	n.DatabaseID = nil
+	reply := convertDiscussionReply(n)
+	if reply.ID != "DC_1" || reply.DatabaseID != 0 || !reply.IsAnswer || reply.UpvoteCount != 4 {
+		t.Errorf("unexpected reply: %+v", reply)
+	}
+}
+
+func TestCollectDiscussionCommentsFollowsCursors(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		switch {
+		case strings.Contains(req.Query, "replies(first: 50, after: $cursor)"):
+			// Second page of replies to DC_2.
+			if req.Variables["id"] != "DC_2" || req.Variables["cursor"] != "r1" {
+				t.Errorf("unexpected replies variables: %v", req.Variables)
+			}
+			fmt.Fprint(w, `{"data":{"node":{"replies":{
+				"pageInfo":{"hasNextPage":false},
+				"nodes":[{"id":"R_2","body":"second reply"}]
+			}}}}`)
+		case strings.Contains(req.Query, "comments(first: 50, after: $cursor)"):
+			// Second page of comments, whose replies span two pages.
+			if req.Variables["id"] != "D_1" || req.Variables["cursor"] != "c1" {
+				t.Errorf("unexpected comments variables: %v", req.Variables)
+			}
+			fmt.Fprint(w, `{"data":{"node":{"comments":{
+				"pageInfo":{"hasNextPage":false},
+				"nodes":[{"id":"DC_2","body":"second","replies":{
+					"pageInfo":{"hasNextPage":true,"endCursor":"r1"},
+					"nodes":[{"id":"R_1","body":"first reply"}]
+				}}]
+			}}}}`)
+		default:
+			t.Errorf("unexpected query: %s", req.Query)
+		}
+	}))
+
+	var first discussionCommentConnection
+	first.PageInfo = pageInfo{HasNextPage: true, EndCursor: "c1"}
+	first.Nodes = append(first.Nodes, struct {
+		discussionCommentNode
+		Replies discussionReplyConnection `graphql:"replies(first: 20)"`
+	}{discussionCommentNode: discussionCommentNode{commentNode: commentNode{ID: "DC_1", Body: "first"}}})
+
+	comments, err := c.collectDiscussionComments(context.Background(), "D_1", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].ID != "DC_1" || comments[1].ID != "DC_2" {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+	replies := comments[1].Replies
+	if len(replies) != 2 || replies[0].ID != "R_1" || replies[1].Body != "second reply" {
+		t.Errorf("unexpected replies: %+v", replies)
+	}
+}
+
+func TestDiscussionEvents(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	chooser := Actor{Login: "mona"}
+	disc := Discussion{
+		Pinned:      true,
+		Closed:      true,
+		ClosedAt:    &t1,
+		StateReason: "RESOLVED",
+		Answer:      &DiscussionAnswer{CommentID: "DC_1", ChosenAt: &t0, ChosenBy: &chooser},
+	}
+	pin := pinnedDiscussion{PinnedBy: &actorNode{TypeName: "User", Login: "octocat"}}
+	pin.CreatedAt.Time = t0
+
+	events := discussionEvents(disc, pin)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: %+v", len(events), events)
+	}
+	if events[0].Type != "pinned" || events[0].Actor.Login != "octocat" {
+		t.Errorf("unexpected pinned event: %+v", events[0])
+	}
+	if events[1].Type != "answer_marked" || events[1].Actor.Login != "mona" || fmt.Sprint(events[1].Details) != "map[comment_id:DC_1]" {
+		t.Errorf("unexpected answer event: %+v", events[1])
+	}
+	if events[2].Type != "closed" || !events[2].CreatedAt.Equal(t1) || fmt.Sprint(events[2].Details) != "map[state_reason:RESOLVED]" {
+		t.Errorf("unexpected closed event: %+v", events[2])
+	}
+
+	if events := discussionEvents(Discussion{}, pinnedDiscussion{}); len(events) != 0 {
+		t.Errorf("got events %+v, want none", events)
+	}
+}
@@ -2,44 +2,128 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 )
 
 type Issue struct {
-	Number    int        `json:"number"`
-	Title     string     `json:"title"`
-	Body      string     `json:"body"`
-	State     string     `json:"state"`
-	Author    Actor      `json:"author"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	ClosedAt  *time.Time `json:"closed_at,omitempty"`
-	Labels    []Label    `json:"labels"`
-	Comments  []Comment  `json:"comments"`
-	Events    []Event    `json:"events"`
+	ID                   string              `json:"id"`
+	DatabaseID           int64               `json:"database_id,omitempty"`
+	URL                  string              `json:"url"`
+	Number               int                 `json:"number"`
+	Title                string              `json:"title"`
+	Body                 string              `json:"body"`
+	State                string              `json:"state"`
+	Author               Actor               `json:"author"`
+	AuthorAssociation    string              `json:"author_association"`
+	CreatedAt            time.Time           `json:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at"`
+	ClosedAt             *time.Time          `json:"closed_at,omitempty"`
+	Labels               []Label             `json:"labels"`
+	Assignees            []Actor             `json:"assignees"`
+	Milestone            *Milestone          `json:"milestone,omitempty"`
+	IssueType            *IssueType          `json:"issue_type,omitempty"`
+	ProjectItems         []ProjectMembership `json:"project_items,omitempty"`
+	Parent               *ItemRef            `json:"parent,omitempty"`
+	SubIssues            []ItemRef           `json:"sub_issues,omitempty"`
+	SubIssuesSummary     *SubIssuesSummary   `json:"sub_issues_summary,omitempty"`
+	TrackedIssues        []ItemRef           `json:"tracked_issues,omitempty"`
+	TrackedInIssues      []ItemRef           `json:"tracked_in_issues,omitempty"`
+	ClosedByPullRequests []ItemRef           `json:"closed_by_pull_requests,omitempty"`
+	Comments             []Comment           `json:"comments"`
+	Events               []Event             `json:"events"`
+	Edits                []Edit              `json:"edits,omitempty"`
 }
 
+// Actor is the author or actor of an item. Type is the GraphQL type of the
+// account (User, Bot, Mannequin, Organization). Ghost is set when the
+// account has been deleted and GitHub no longer reports it.
 type Actor struct {
-	Login string `json:"login"`
+	ID        string `json:"id,omitempty"`
+	Login     string `json:"login"`
+	Type      string `json:"type,omitempty"`
+	Name      string `json:"name,omitempty"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Ghost     bool   `json:"ghost,omitempty"`
 }
 
 type Label struct {
-	Name  string `json:"name"`
-	Color string `json:"color"`
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+}
+
+type Milestone struct {
+	ID          string     `json:"id"`
+	URL         string     `json:"url"`
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	State       string     `json:"state"`
+	DueOn       *time.Time `json:"due_on,omitempty"`
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+}
+
+type IssueType struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description,omitempty"`
+}
+
+// ItemRef points at another issue or pull request, possibly in a different
+// repository. Repository is in owner/name form.
+type ItemRef struct {
+	ID         string `json:"id"`
+	URL        string `json:"url"`
+	Repository string `json:"repository"`
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	State      string `json:"state"`
+}
+
+type SubIssuesSummary struct {
+	Total            int `json:"total"`
+	Completed        int `json:"completed"`
+	PercentCompleted int `json:"percent_completed"`
 }
 
 type Comment struct {
-	Author    Actor     `json:"author"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                string     `json:"id"`
+	DatabaseID        int64      `json:"database_id,omitempty"`
+	URL               string     `json:"url"`
+	Author            Actor      `json:"author"`
+	AuthorAssociation string     `json:"author_association"`
+	Body              string     `json:"body"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	LastEditedAt      *time.Time `json:"last_edited_at,omitempty"`
+	IsMinimized       bool       `json:"is_minimized"`
+	MinimizedReason   string     `json:"minimized_reason,omitempty"`
+	Edits             []Edit     `json:"edits,omitempty"`
+}
+
+// Edit is a single revision of an issue, pull request, discussion or comment
+// body. Diff holds the content of that revision as reported by GitHub.
+type Edit struct {
+	ID        string     `json:"id"`
+	Editor    Actor      `json:"editor"`
+	EditedAt  time.Time  `json:"edited_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *Actor     `json:"deleted_by,omitempty"`
+	Diff      string     `json:"diff"`
 }
 
 type Event struct {
+	ID        string    `json:"id"`
 	Type      string    `json:"type"`
-	Actor     Actor     `json:"actor"`
+	Actor     Actor     `json:"actor,omitzero"`
 	CreatedAt time.Time `json:"created_at"`
 	Details   any       `json:"details,omitempty"`
 }
@@ -52,64 +136,168 @@ type issueQuery struct {
 				EndCursor   githubv4.String
 			}
 			Nodes []struct {
-				Number    githubv4.Int
-				Title     githubv4.String
-				Body      githubv4.String
-				State     githubv4.String
-				CreatedAt githubv4.DateTime
-				UpdatedAt githubv4.DateTime
-				ClosedAt  *githubv4.DateTime
-				Author    struct {
-					Login githubv4.String
-				}
-				Labels struct {
-					Nodes []struct {
-						Name  githubv4.String
-						Color githubv4.String
-					}
+				ID                githubv4.String
+				FullDatabaseID    *githubv4.String
+				URL               githubv4.String
+				Number            githubv4.Int
+				Title             githubv4.String
+				Body              githubv4.String
+				State             githubv4.String
+				CreatedAt         githubv4.DateTime
+				UpdatedAt         githubv4.DateTime
+				ClosedAt          *githubv4.DateTime
+				Author            *actorNode
+				AuthorAssociation githubv4.String
+				UserContentEdits  userContentEdits `graphql:"userContentEdits(first: 20)"`
+				Labels            struct {
+					Nodes []labelNode
 				} `graphql:"labels(first: 50)"`
-				Comments struct {
-					Nodes []struct {
-						Author struct {
-							Login githubv4.String
-						}
-						Body      githubv4.String
-						CreatedAt githubv4.DateTime
-						UpdatedAt githubv4.DateTime
-					}
+				Assignees struct {
+					Nodes []userNode
+				} `graphql:"assignees(first: 20)"`
+				Milestone *milestoneNode
+				IssueType *struct {
+					ID          githubv4.String
+					Name        githubv4.String
+					Color       githubv4.String
+					Description *githubv4.String
+				}
+				Parent           *itemRefNode
+				SubIssues        itemRefConnection `graphql:"subIssues(first: 50)"`
+				SubIssuesSummary struct {
+					Total            githubv4.Int
+					Completed        githubv4.Int
+					PercentCompleted githubv4.Int
+				}
+				TrackedIssues                  itemRefConnection `graphql:"trackedIssues(first: 50)"`
+				TrackedInIssues                itemRefConnection `graphql:"trackedInIssues(first: 50)"`
+				ClosedByPullRequestsReferences itemRefConnection `graphql:"closedByPullRequestsReferences(first: 20, includeClosedPrs: true)"`
+				Comments                       struct {
+					Nodes []issueCommentNode
 				} `graphql:"comments(first: 50)"`
-				TimelineItems struct {
-					Nodes []issueTimelineItem
-				} `graphql:"timelineItems(first: 50)"`
+				TimelineItems issueTimelineConnection `graphql:"timelineItems(first: 50)"`
 			}
 		} `graphql:"issues(first: 20, orderBy: {field: UPDATED_AT, direction: DESC}, filterBy: {since: $since}, after: $cursor)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
 }
 
+type nodeFragment struct {
+	ID githubv4.String
+}
+
+type actorNode struct {
+	TypeName     string `graphql:"__typename"`
+	Login        githubv4.String
+	AvatarURL    githubv4.String
+	URL          githubv4.String
+	Node         nodeFragment                    `graphql:"... on Node"`
+	User         struct{ Name *githubv4.String } `graphql:"... on User"`
+	Organization struct{ Name *githubv4.String } `graphql:"... on Organization"`
+}
+
+// userNode is an actor queried from a User-typed field, where the
+// fragments in actorNode would not validate.
+type userNode struct {
+	ID        githubv4.String
+	Login     githubv4.String
+	Name      *githubv4.String
+	AvatarURL githubv4.String
+	URL       githubv4.String
+}
+
+type milestoneNode struct {
+	ID          githubv4.String
+	URL         githubv4.String
+	Number      githubv4.Int
+	Title       githubv4.String
+	Description *githubv4.String
+	State       githubv4.String
+	DueOn       *githubv4.DateTime
+	ClosedAt    *githubv4.DateTime
+}
+
+type itemRefNode struct {
+	ID         githubv4.String
+	URL        githubv4.String
+	Number     githubv4.Int
+	Title      githubv4.String
+	State      githubv4.String
+	Repository struct {
+		NameWithOwner githubv4.String
+	}
+}
+
+type itemRefConnection struct {
+	PageInfo pageInfo
+	Nodes    []itemRefNode
+}
+
+type labelNode struct {
+	ID          githubv4.String
+	URL         githubv4.String
+	Name        githubv4.String
+	Color       githubv4.String
+	Description *githubv4.String
+}
+
+type commentNode struct {
+	ID                githubv4.String
+	URL               githubv4.String
+	Author            *actorNode
+	AuthorAssociation githubv4.String
+	Body              githubv4.String
+	CreatedAt         githubv4.DateTime
+	UpdatedAt         githubv4.DateTime
+	LastEditedAt      *githubv4.DateTime
+	IsMinimized       githubv4.Boolean
+	MinimizedReason   *githubv4.String
+	UserContentEdits  userContentEdits `graphql:"userContentEdits(first: 20)"`
+}
+
+// issueCommentNode is a comment on an issue or pull request. Unlike the
+// other comment types, its database ID may exceed the 32-bit databaseId
+// field and is read from fullDatabaseId instead.
+type issueCommentNode struct {
+	commentNode
+	FullDatabaseID *githubv4.String
+}
+
+type userContentEdits struct {
+	PageInfo pageInfo
+	Nodes    []struct {
+		ID        githubv4.String
+		Editor    *actorNode
+		EditedAt  githubv4.DateTime
+		DeletedAt *githubv4.DateTime
+		DeletedBy *actorNode
+		Diff      *githubv4.String
+	}
+}
+
 type closedEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 }
 
 type reopenedEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 }
 
 type labeledEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 	Label     struct{ Name githubv4.String }
 }
 
 type unlabeledEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 	Label     struct{ Name githubv4.String }
 }
 
 type assignedEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 	Assignee  struct {
 		User struct{ Login githubv4.String } `graphql:"... on User"`
@@ -117,7 +305,7 @@ type assignedEvent struct {
 }
 
 type unassignedEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 	Assignee  struct {
 		User struct{ Login githubv4.String } `graphql:"... on User"`
@@ -125,23 +313,170 @@ type unassignedEvent struct {
 }
 
 type crossReferencedEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor           *actorNode
+	CreatedAt       githubv4.DateTime
+	WillCloseTarget githubv4.Boolean
+	Source          struct {
+		TypeName string                 `graphql:"__typename"`
+		Issue    crossReferencingSource `graphql:"... on Issue"`
+		PR       crossReferencingSource `graphql:"... on PullRequest"`
+	}
+}
+
+// crossReferencingSource leaves out state, whose enum type differs between
+// issues and pull requests and would make the two fragments conflict.
+type crossReferencingSource struct {
+	Number     githubv4.Int
+	Title      githubv4.String
+	URL        githubv4.String
+	Repository struct {
+		NameWithOwner githubv4.String
+	}
+}
+
+type renamedTitleEvent struct {
+	Actor         *actorNode
+	CreatedAt     githubv4.DateTime
+	PreviousTitle githubv4.String
+	CurrentTitle  githubv4.String
+}
+
+type milestonedEvent struct {
+	Actor          *actorNode
+	CreatedAt      githubv4.DateTime
+	MilestoneTitle githubv4.String
+}
+
+type demilestonedEvent struct {
+	Actor          *actorNode
+	CreatedAt      githubv4.DateTime
+	MilestoneTitle githubv4.String
+}
+
+type lockedEvent struct {
+	Actor      *actorNode
+	CreatedAt  githubv4.DateTime
+	LockReason *githubv4.String
+}
+
+type unlockedEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+}
+
+type markedAsDuplicateEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+	Canonical duplicateCanonical
+}
+
+type unmarkedAsDuplicateEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+	Canonical duplicateCanonical
+}
+
+type duplicateCanonical struct {
+	Issue crossReferencingSource `graphql:"... on Issue"`
+	PR    crossReferencingSource `graphql:"... on PullRequest"`
+}
+
+type pinnedEvent struct {
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
-	Source    struct {
-		Issue struct{ Number githubv4.Int } `graphql:"... on Issue"`
-		PR    struct{ Number githubv4.Int } `graphql:"... on PullRequest"`
+}
+
+type unpinnedEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+}
+
+type transferredEvent struct {
+	Actor          *actorNode
+	CreatedAt      githubv4.DateTime
+	FromRepository struct {
+		NameWithOwner githubv4.String
 	}
 }
 
+type convertedToDiscussionEvent struct {
+	Actor      *actorNode
+	CreatedAt  githubv4.DateTime
+	Discussion *struct {
+		Number githubv4.Int
+		URL    githubv4.String
+	}
+}
+
+// sharedTimelineItem holds the timeline events that issues and pull
+// requests have in common.
+type sharedTimelineItem struct {
+	ClosedEvent              closedEvent              `graphql:"... on ClosedEvent"`
+	ReopenedEvent            reopenedEvent            `graphql:"... on ReopenedEvent"`
+	LabeledEvent             labeledEvent             `graphql:"... on LabeledEvent"`
+	UnlabeledEvent           unlabeledEvent           `graphql:"... on UnlabeledEvent"`
+	AssignedEvent            assignedEvent            `graphql:"... on AssignedEvent"`
+	UnassignedEvent          unassignedEvent          `graphql:"... on UnassignedEvent"`
+	CrossReferencedEvent     crossReferencedEvent     `graphql:"... on CrossReferencedEvent"`
+	RenamedTitleEvent        renamedTitleEvent        `graphql:"... on RenamedTitleEvent"`
+	MilestonedEvent          milestonedEvent          `graphql:"... on MilestonedEvent"`
+	DemilestonedEvent        demilestonedEvent        `graphql:"... on DemilestonedEvent"`
+	LockedEvent              lockedEvent              `graphql:"... on LockedEvent"`
+	UnlockedEvent            unlockedEvent            `graphql:"... on UnlockedEvent"`
+	MarkedAsDuplicateEvent   markedAsDuplicateEvent   `graphql:"... on MarkedAsDuplicateEvent"`
+	UnmarkedAsDuplicateEvent unmarkedAsDuplicateEvent `graphql:"... on UnmarkedAsDuplicateEvent"`
+}
+
 type issueTimelineItem struct {
-	TypeName             string               `graphql:"__typename"`
-	ClosedEvent          closedEvent          `graphql:"... on ClosedEvent"`
-	ReopenedEvent        reopenedEvent        `graphql:"... on ReopenedEvent"`
-	LabeledEvent         labeledEvent         `graphql:"... on LabeledEvent"`
-	UnlabeledEvent       unlabeledEvent       `graphql:"... on UnlabeledEvent"`
-	AssignedEvent        assignedEvent        `graphql:"... on AssignedEvent"`
-	UnassignedEvent      unassignedEvent      `graphql:"... on UnassignedEvent"`
-	CrossReferencedEvent crossReferencedEvent `graphql:"... on CrossReferencedEvent"`
+	TypeName string       `graphql:"__typename"`
+	Node     nodeFragment `graphql:"... on Node"`
+	sharedTimelineItem
+	PinnedEvent                pinnedEvent                `graphql:"... on PinnedEvent"`
+	UnpinnedEvent              unpinnedEvent              `graphql:"... on UnpinnedEvent"`
+	TransferredEvent           transferredEvent           `graphql:"... on TransferredEvent"`
+	ConvertedToDiscussionEvent convertedToDiscussionEvent `graphql:"... on ConvertedToDiscussionEvent"`
+}
+
+type issueTimelineConnection struct {
+	PageInfo pageInfo
+	Nodes    []issueTimelineItem
+}
+
+// issueTimelineQuery fetches a further page of the timeline of an issue.
+type issueTimelineQuery struct {
+	Node struct {
+		Issue struct {
+			TimelineItems issueTimelineConnection `graphql:"timelineItems(first: 100, after: $cursor)"`
+		} `graphql:"... on Issue"`
+	} `graphql:"node(id: $id)"`
+}
+
+// collectIssueEvents converts the first page of the timeline of an issue and
+// follows the cursor until every event has been fetched.
+func (c *Client) collectIssueEvents(ctx context.Context, id githubv4.String, conn issueTimelineConnection) ([]Event, error) {
+	var events []Event
+	for {
+		for _, ti := range conn.Nodes {
+			event := convertTimelineEvent(ti)
+			if event != nil {
+				event.ID = string(ti.Node.ID)
+				events = append(events, *event)
+			}
+		}
+
+		if !conn.PageInfo.HasNextPage {
+			return events, nil
+		}
+		var q issueTimelineQuery
+		vars := map[string]any{
+			"id":     githubv4.ID(string(id)),
+			"cursor": conn.PageInfo.EndCursor,
+		}
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+		conn = q.Node.Issue.TimelineItems
+	}
 }
 
 func (c *Client) FetchIssues(ctx context.Context, owner, repo string, since *time.Time) ([]Issue, error) {
@@ -169,41 +504,85 @@ func (c *Client) FetchIssues(ctx context.Context, owner, repo string, since *tim
 
 		for _, node := range q.Repository.Issues.Nodes {
 			issue := Issue{
-				Number:    int(node.Number),
-				Title:     string(node.Title),
-				Body:      string(node.Body),
-				State:     string(node.State),
-				Author:    Actor{Login: string(node.Author.Login)},
-				CreatedAt: node.CreatedAt.Time,
-				UpdatedAt: node.UpdatedAt.Time,
+				ID:                string(node.ID),
+				URL:               string(node.URL),
+				Number:            int(node.Number),
+				Title:             string(node.Title),
+				Body:              string(node.Body),
+				State:             string(node.State),
+				Author:            convertActor(node.Author),
+				AuthorAssociation: string(node.AuthorAssociation),
+				CreatedAt:         node.CreatedAt.Time,
+				UpdatedAt:         node.UpdatedAt.Time,
 			}
 
+			edits, err := c.collectEdits(ctx, node.ID, node.UserContentEdits)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch edits of issue %d: %w", issue.Number, err)
+			}
+			issue.Edits = edits
+			issue.DatabaseID = parseDatabaseID(node.FullDatabaseID)
 			if node.ClosedAt != nil {
 				t := node.ClosedAt.Time
 				issue.ClosedAt = &t
 			}
 
 			for _, l := range node.Labels.Nodes {
-				issue.Labels = append(issue.Labels, Label{
-					Name:  string(l.Name),
-					Color: string(l.Color),
-				})
+				issue.Labels = append(issue.Labels, convertLabel(l))
 			}
 
-			for _, c := range node.Comments.Nodes {
-				issue.Comments = append(issue.Comments, Comment{
-					Author:    Actor{Login: string(c.Author.Login)},
-					Body:      string(c.Body),
-					CreatedAt: c.CreatedAt.Time,
-					UpdatedAt: c.UpdatedAt.Time,
-				})
+			for _, a := range node.Assignees.Nodes {
+				issue.Assignees = append(issue.Assignees, convertUser(a))
+			}
+			if node.Milestone != nil {
+				m := convertMilestone(*node.Milestone)
+				issue.Milestone = &m
+			}
+			if node.Parent != nil {
+				parent := convertItemRef(*node.Parent)
+				issue.Parent = &parent
+			}
+			if issue.SubIssues, err = c.collectItemRefs(ctx, node.ID, node.SubIssues, func() itemRefPage { return &subIssuesQuery{} }); err != nil {
+				return nil, fmt.Errorf("failed to fetch sub-issues of issue %d: %w", issue.Number, err)
+			}
+			if node.SubIssuesSummary.Total > 0 {
+				issue.SubIssuesSummary = &SubIssuesSummary{
+					Total:            int(node.SubIssuesSummary.Total),
+					Completed:        int(node.SubIssuesSummary.Completed),
+					PercentCompleted: int(node.SubIssuesSummary.PercentCompleted),
+				}
+			}
+			if issue.TrackedIssues, err = c.collectItemRefs(ctx, node.ID, node.TrackedIssues, func() itemRefPage { return &trackedIssuesQuery{} }); err != nil {
+				return nil, fmt.Errorf("failed to fetch tracked issues of issue %d: %w", issue.Number, err)
+			}
+			if issue.TrackedInIssues, err = c.collectItemRefs(ctx, node.ID, node.TrackedInIssues, func() itemRefPage { return &trackedInIssuesQuery{} }); err != nil {
+				return nil, fmt.Errorf("failed to fetch tracking issues of issue %d: %w", issue.Number, err)
+			}
+			if issue.ClosedByPullRequests, err = c.collectItemRefs(ctx, node.ID, node.ClosedByPullRequestsReferences, func() itemRefPage { return &closedByPullRequestsQuery{} }); err != nil {
+				return nil, fmt.Errorf("failed to fetch closing pull requests of issue %d: %w", issue.Number, err)
+			}
+			if node.IssueType != nil {
+				issue.IssueType = &IssueType{
+					ID:    string(node.IssueType.ID),
+					Name:  string(node.IssueType.Name),
+					Color: string(node.IssueType.Color),
+				}
+				if node.IssueType.Description != nil {
+					issue.IssueType.Description = string(*node.IssueType.Description)
+				}
 			}
 
-			for _, ti := range node.TimelineItems.Nodes {
-				event := convertTimelineEvent(ti)
-				if event != nil {
-					issue.Events = append(issue.Events, *event)
+			for _, n := range node.Comments.Nodes {
+				comment, err := c.fetchComment(ctx, n.commentNode)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch comment edits of issue %d: %w", issue.Number, err)
 				}
+				comment.DatabaseID = parseDatabaseID(n.FullDatabaseID)
+				issue.Comments = append(issue.Comments, comment)
+			}
+
+			if issue.Events, err = c.collectIssueEvents(ctx, node.ID, node.TimelineItems); err != nil {
+				return nil, fmt.Errorf("failed to fetch timeline of issue %d: %w", issue.Number, err)
 			}
 
 			allIssues = append(allIssues, issue)
@@ -218,55 +597,472 @@ func (c *Client) FetchIssues(ctx context.Context, owner, repo string, since *tim
 	return allIssues, nil
 }
 
+// ghostLogin is the login GitHub shows in place of deleted accounts.
+const ghostLogin = "ghost"
+
+func convertActor(a *actorNode) Actor {
+	if a == nil {
+		return Actor{Login: ghostLogin, Ghost: true}
+	}
+	actor := Actor{
+		ID:        string(a.Node.ID),
+		Login:     string(a.Login),
+		Type:      a.TypeName,
+		AvatarURL: string(a.AvatarURL),
+		URL:       string(a.URL),
+		Ghost:     string(a.Login) == ghostLogin,
+	}
+	switch {
+	case a.User.Name != nil:
+		actor.Name = string(*a.User.Name)
+	case a.Organization.Name != nil:
+		actor.Name = string(*a.Organization.Name)
+	}
+	return actor
+}
+
+// parseDatabaseID parses a fullDatabaseId, a BigInt that GitHub serialises
+// as a string. It returns 0 if the ID is missing or malformed.
+func parseDatabaseID(s *githubv4.String) int64 {
+	if s == nil {
+		return 0
+	}
+	id, err := strconv.ParseInt(string(*s), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func convertUser(u userNode) Actor {
+	actor := Actor{
+		ID:        string(u.ID),
+		Login:     string(u.Login),
+		Type:      "User",
+		AvatarURL: string(u.AvatarURL),
+		URL:       string(u.URL),
+	}
+	if u.Name != nil {
+		actor.Name = string(*u.Name)
+	}
+	return actor
+}
+
+func convertMilestone(m milestoneNode) Milestone {
+	milestone := Milestone{
+		ID:     string(m.ID),
+		URL:    string(m.URL),
+		Number: int(m.Number),
+		Title:  string(m.Title),
+		State:  string(m.State),
+	}
+	if m.Description != nil {
+		milestone.Description = string(*m.Description)
+	}
+	if m.DueOn != nil {
+		t := m.DueOn.Time
+		milestone.DueOn = &t
+	}
+	if m.ClosedAt != nil {
+		t := m.ClosedAt.Time
+		milestone.ClosedAt = &t
+	}
+	return milestone
+}
+
+func convertCrossReference(e crossReferencedEvent) *Event {
+	source := e.Source.Issue
+	if e.Source.TypeName == "PullRequest" {
+		source = e.Source.PR
+	}
+	return &Event{
+		Type:      "cross-referenced",
+		Actor:     convertActor(e.Actor),
+		CreatedAt: e.CreatedAt.Time,
+		Details: map[string]any{
+			"source_type":       e.Source.TypeName,
+			"source_repository": string(source.Repository.NameWithOwner),
+			"source_number":     int(source.Number),
+			"source_title":      string(source.Title),
+			"source_url":        string(source.URL),
+			"will_close_target": bool(e.WillCloseTarget),
+		},
+	}
+}
+
+func convertItemRef(r itemRefNode) ItemRef {
+	return ItemRef{
+		ID:         string(r.ID),
+		URL:        string(r.URL),
+		Repository: string(r.Repository.NameWithOwner),
+		Number:     int(r.Number),
+		Title:      string(r.Title),
+		State:      string(r.State),
+	}
+}
+
+func convertLabel(l labelNode) Label {
+	label := Label{
+		ID:    string(l.ID),
+		URL:   string(l.URL),
+		Name:  string(l.Name),
+		Color: string(l.Color),
+	}
+	if l.Description != nil {
+		label.Description = string(*l.Description)
+	}
+	return label
+}
+
+func convertComment(c commentNode) Comment {
+	comment := Comment{
+		ID:                string(c.ID),
+		URL:               string(c.URL),
+		Author:            convertActor(c.Author),
+		AuthorAssociation: string(c.AuthorAssociation),
+		Body:              string(c.Body),
+		CreatedAt:         c.CreatedAt.Time,
+		UpdatedAt:         c.UpdatedAt.Time,
+		IsMinimized:       bool(c.IsMinimized),
+	}
+	if c.LastEditedAt != nil {
+		t := c.LastEditedAt.Time
+		comment.LastEditedAt = &t
+	}
+	if c.MinimizedReason != nil {
+		comment.MinimizedReason = string(*c.MinimizedReason)
+	}
+	return comment
+}
+
+// userContentEditsQuery fetches a further page of the edits of any issue,
+// pull request, discussion or comment body.
+type userContentEditsQuery struct {
+	Node struct {
+		Comment struct {
+			UserContentEdits userContentEdits `graphql:"userContentEdits(first: 100, after: $cursor)"`
+		} `graphql:"... on Comment"`
+	} `graphql:"node(id: $id)"`
+}
+
+// itemRefPage is a query fetching a further page of a connection listing
+// other issues and pull requests.
+type itemRefPage interface {
+	connection() itemRefConnection
+}
+
+type subIssuesQuery struct {
+	Node struct {
+		Issue struct {
+			SubIssues itemRefConnection `graphql:"subIssues(first: 100, after: $cursor)"`
+		} `graphql:"... on Issue"`
+	} `graphql:"node(id: $id)"`
+}
+
+func (q *subIssuesQuery) connection() itemRefConnection { return q.Node.Issue.SubIssues }
+
+type trackedIssuesQuery struct {
+	Node struct {
+		Issue struct {
+			TrackedIssues itemRefConnection `graphql:"trackedIssues(first: 100, after: $cursor)"`
+		} `graphql:"... on Issue"`
+	} `graphql:"node(id: $id)"`
+}
+
+func (q *trackedIssuesQuery) connection() itemRefConnection { return q.Node.Issue.TrackedIssues }
+
+type trackedInIssuesQuery struct {
+	Node struct {
+		Issue struct {
+			TrackedInIssues itemRefConnection `graphql:"trackedInIssues(first: 100, after: $cursor)"`
+		} `graphql:"... on Issue"`
+	} `graphql:"node(id: $id)"`
+}
+
+func (q *trackedInIssuesQuery) connection() itemRefConnection { return q.Node.Issue.TrackedInIssues }
+
+type closedByPullRequestsQuery struct {
+	Node struct {
+		Issue struct {
+			ClosedByPullRequestsReferences itemRefConnection `graphql:"closedByPullRequestsReferences(first: 100, includeClosedPrs: true, after: $cursor)"`
+		} `graphql:"... on Issue"`
+	} `graphql:"node(id: $id)"`
+}
+
+func (q *closedByPullRequestsQuery) connection() itemRefConnection {
+	return q.Node.Issue.ClosedByPullRequestsReferences
+}
+
+type closingIssuesQuery struct {
+	Node struct {
+		PullRequest struct {
+			ClosingIssuesReferences itemRefConnection `graphql:"closingIssuesReferences(first: 100, after: $cursor)"`
+		} `graphql:"... on PullRequest"`
+	} `graphql:"node(id: $id)"`
+}
+
+func (q *closingIssuesQuery) connection() itemRefConnection {
+	return q.Node.PullRequest.ClosingIssuesReferences
+}
+
+// collectItemRefs converts the first page of a connection listing issues and
+// pull requests and follows its cursor, using queries made by newQuery,
+// until every page has been fetched.
+func (c *Client) collectItemRefs(ctx context.Context, id githubv4.String, conn itemRefConnection, newQuery func() itemRefPage) ([]ItemRef, error) {
+	var refs []ItemRef
+	for {
+		for _, n := range conn.Nodes {
+			refs = append(refs, convertItemRef(n))
+		}
+
+		if !conn.PageInfo.HasNextPage {
+			return refs, nil
+		}
+		q := newQuery()
+		vars := map[string]any{
+			"id":     githubv4.ID(string(id)),
+			"cursor": conn.PageInfo.EndCursor,
+		}
+		if err := c.gql.Query(ctx, q, vars); err != nil {
+			return nil, err
+		}
+		conn = q.connection()
+	}
+}
+
+// collectEdits converts the first page of edits of a body and follows the
+// cursor until every revision has been fetched.
+func (c *Client) collectEdits(ctx context.Context, id githubv4.String, conn userContentEdits) ([]Edit, error) {
+	edits := convertEdits(conn)
+	if !conn.PageInfo.HasNextPage {
+		return edits, nil
+	}
+	more, err := c.fetchEdits(ctx, id, &conn.PageInfo.EndCursor)
+	if err != nil {
+		return nil, err
+	}
+	return append(edits, more...), nil
+}
+
+// fetchEdits fetches the edits of a body after cursor, or all of them if
+// cursor is nil. It is used for bodies whose edits are not part of the
+// query that fetched them.
+func (c *Client) fetchEdits(ctx context.Context, id githubv4.String, cursor *githubv4.String) ([]Edit, error) {
+	var edits []Edit
+	for {
+		var q userContentEditsQuery
+		vars := map[string]any{
+			"id":     githubv4.ID(string(id)),
+			"cursor": cursor,
+		}
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+		conn := q.Node.Comment.UserContentEdits
+		edits = append(edits, convertEdits(conn)...)
+
+		if !conn.PageInfo.HasNextPage {
+			return edits, nil
+		}
+		cursor = &conn.PageInfo.EndCursor
+	}
+}
+
+// fetchComment converts a comment along with all of its edits.
+func (c *Client) fetchComment(ctx context.Context, n commentNode) (Comment, error) {
+	comment := convertComment(n)
+	edits, err := c.collectEdits(ctx, n.ID, n.UserContentEdits)
+	if err != nil {
+		return Comment{}, err
+	}
+	comment.Edits = edits
+	return comment, nil
+}
+
+func convertEdits(e userContentEdits) []Edit {
+	var edits []Edit
+	for _, n := range e.Nodes {
+		edit := Edit{
+			ID:       string(n.ID),
+			Editor:   convertActor(n.Editor),
+			EditedAt: n.EditedAt.Time,
+		}
+		if n.DeletedAt != nil {
+			t := n.DeletedAt.Time
+			edit.DeletedAt = &t
+			deletedBy := convertActor(n.DeletedBy)
+			edit.DeletedBy = &deletedBy
+		}
+		if n.Diff != nil {
+			edit.Diff = string(*n.Diff)
+		}
+		edits = append(edits, edit)
+	}
+	return edits
+}
+
 func convertTimelineEvent(ti issueTimelineItem) *Event {
 	switch ti.TypeName {
+	case "PinnedEvent":
+		return &Event{
+			Type:      "pinned",
+			Actor:     convertActor(ti.PinnedEvent.Actor),
+			CreatedAt: ti.PinnedEvent.CreatedAt.Time,
+		}
+	case "UnpinnedEvent":
+		return &Event{
+			Type:      "unpinned",
+			Actor:     convertActor(ti.UnpinnedEvent.Actor),
+			CreatedAt: ti.UnpinnedEvent.CreatedAt.Time,
+		}
+	case "TransferredEvent":
+		return &Event{
+			Type:      "transferred",
+			Actor:     convertActor(ti.TransferredEvent.Actor),
+			CreatedAt: ti.TransferredEvent.CreatedAt.Time,
+			Details:   map[string]string{"from_repository": string(ti.TransferredEvent.FromRepository.NameWithOwner)},
+		}
+	case "ConvertedToDiscussionEvent":
+		event := &Event{
+			Type:      "converted_to_discussion",
+			Actor:     convertActor(ti.ConvertedToDiscussionEvent.Actor),
+			CreatedAt: ti.ConvertedToDiscussionEvent.CreatedAt.Time,
+		}
+		if d := ti.ConvertedToDiscussionEvent.Discussion; d != nil {
+			event.Details = map[string]any{
+				"discussion_number": int(d.Number),
+				"discussion_url":    string(d.URL),
+			}
+		}
+		return event
+	default:
+		return convertSharedTimelineEvent(ti.TypeName, ti.sharedTimelineItem)
+	}
+}
+
+func convertSharedTimelineEvent(typeName string, ti sharedTimelineItem) *Event {
+	switch typeName {
 	case "ClosedEvent":
 		return &Event{
 			Type:      "closed",
-			Actor:     Actor{Login: string(ti.ClosedEvent.Actor.Login)},
+			Actor:     convertActor(ti.ClosedEvent.Actor),
 			CreatedAt: ti.ClosedEvent.CreatedAt.Time,
 		}
 	case "ReopenedEvent":
 		return &Event{
 			Type:      "reopened",
-			Actor:     Actor{Login: string(ti.ReopenedEvent.Actor.Login)},
+			Actor:     convertActor(ti.ReopenedEvent.Actor),
 			CreatedAt: ti.ReopenedEvent.CreatedAt.Time,
 		}
 	case "LabeledEvent":
 		return &Event{
 			Type:      "labeled",
-			Actor:     Actor{Login: string(ti.LabeledEvent.Actor.Login)},
+			Actor:     convertActor(ti.LabeledEvent.Actor),
 			CreatedAt: ti.LabeledEvent.CreatedAt.Time,
 			Details:   map[string]string{"label": string(ti.LabeledEvent.Label.Name)},
 		}
 	case "UnlabeledEvent":
 		return &Event{
 			Type:      "unlabeled",
-			Actor:     Actor{Login: string(ti.UnlabeledEvent.Actor.Login)},
+			Actor:     convertActor(ti.UnlabeledEvent.Actor),
 			CreatedAt: ti.UnlabeledEvent.CreatedAt.Time,
 			Details:   map[string]string{"label": string(ti.UnlabeledEvent.Label.Name)},
 		}
 	case "AssignedEvent":
 		return &Event{
 			Type:      "assigned",
-			Actor:     Actor{Login: string(ti.AssignedEvent.Actor.Login)},
+			Actor:     convertActor(ti.AssignedEvent.Actor),
 			CreatedAt: ti.AssignedEvent.CreatedAt.Time,
 			Details:   map[string]string{"assignee": string(ti.AssignedEvent.Assignee.User.Login)},
 		}
 	case "UnassignedEvent":
 		return &Event{
 			Type:      "unassigned",
-			Actor:     Actor{Login: string(ti.UnassignedEvent.Actor.Login)},
+			Actor:     convertActor(ti.UnassignedEvent.Actor),
 			CreatedAt: ti.UnassignedEvent.CreatedAt.Time,
 			Details:   map[string]string{"assignee": string(ti.UnassignedEvent.Assignee.User.Login)},
 		}
 	case "CrossReferencedEvent":
+		return convertCrossReference(ti.CrossReferencedEvent)
+	case "RenamedTitleEvent":
+		return &Event{
+			Type:      "renamed",
+			Actor:     convertActor(ti.RenamedTitleEvent.Actor),
+			CreatedAt: ti.RenamedTitleEvent.CreatedAt.Time,
+			Details: map[string]string{
+				"from": string(ti.RenamedTitleEvent.PreviousTitle),
+				"to":   string(ti.RenamedTitleEvent.CurrentTitle),
+			},
+		}
+	case "MilestonedEvent":
+		return &Event{
+			Type:      "milestoned",
+			Actor:     convertActor(ti.MilestonedEvent.Actor),
+			CreatedAt: ti.MilestonedEvent.CreatedAt.Time,
+			Details:   map[string]string{"milestone": string(ti.MilestonedEvent.MilestoneTitle)},
+		}
+	case "DemilestonedEvent":
 		return &Event{
-			Type:      "cross-referenced",
-			Actor:     Actor{Login: string(ti.CrossReferencedEvent.Actor.Login)},
-			CreatedAt: ti.CrossReferencedEvent.CreatedAt.Time,
+			Type:      "demilestoned",
+			Actor:     convertActor(ti.DemilestonedEvent.Actor),
+			CreatedAt: ti.DemilestonedEvent.CreatedAt.Time,
+			Details:   map[string]string{"milestone": string(ti.DemilestonedEvent.MilestoneTitle)},
 		}
+	case "LockedEvent":
+		event := &Event{
+			Type:      "locked",
+			Actor:     convertActor(ti.LockedEvent.Actor),
+			CreatedAt: ti.LockedEvent.CreatedAt.Time,
+		}
+		if ti.LockedEvent.LockReason != nil {
+			event.Details = map[string]string{"reason": string(*ti.LockedEvent.LockReason)}
+		}
+		return event
+	case "UnlockedEvent":
+		return &Event{
+			Type:      "unlocked",
+			Actor:     convertActor(ti.UnlockedEvent.Actor),
+			CreatedAt: ti.UnlockedEvent.CreatedAt.Time,
+		}
+	case "MarkedAsDuplicateEvent":
+		event := &Event{
+			Type:      "marked_as_duplicate",
+			Actor:     convertActor(ti.MarkedAsDuplicateEvent.Actor),
+			CreatedAt: ti.MarkedAsDuplicateEvent.CreatedAt.Time,
+		}
+		if details := canonicalDetails(ti.MarkedAsDuplicateEvent.Canonical); details != nil {
+			event.Details = details
+		}
+		return event
+	case "UnmarkedAsDuplicateEvent":
+		event := &Event{
+			Type:      "unmarked_as_duplicate",
+			Actor:     convertActor(ti.UnmarkedAsDuplicateEvent.Actor),
+			CreatedAt: ti.UnmarkedAsDuplicateEvent.CreatedAt.Time,
+		}
+		if details := canonicalDetails(ti.UnmarkedAsDuplicateEvent.Canonical); details != nil {
+			event.Details = details
+		}
+		return event
 	default:
 		return nil
 	}
 }
+
+// canonicalDetails describes the item a duplicate points at, or returns nil
+// if there is none. Only one of the two fragments is populated, depending on
+// the canonical item's type.
+func canonicalDetails(c duplicateCanonical) map[string]any {
+	canonical := c.Issue
+	if canonical.Number == 0 {
+		canonical = c.PR
+	}
+	if canonical.Number == 0 {
+		return nil
+	}
+	return map[string]any{
+		"canonical_repository": string(canonical.Repository.NameWithOwner),
+		"canonical_number":     int(canonical.Number),
+		"canonical_url":        string(canonical.URL),
+	}
+}
@@ -0,0 +1,345 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+func TestConvertEdits(t *testing.T) {
+	diff := githubv4.String("new body")
+	editedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := editedAt.Add(time.Hour)
+
+	var conn userContentEdits
+	conn.Nodes = append(conn.Nodes, struct {
+		ID        githubv4.String
+		Editor    *actorNode
+		EditedAt  githubv4.DateTime
+		DeletedAt *githubv4.DateTime
+		DeletedBy *actorNode
+		Diff      *githubv4.String
+	}{
+		ID:        "UCE_1",
+		Editor:    &actorNode{TypeName: "User", Login: "octocat"},
+		EditedAt:  githubv4.DateTime{Time: editedAt},
+		DeletedAt: &githubv4.DateTime{Time: deletedAt},
+		Diff:      &diff,
+	})
+
+	edits := convertEdits(conn)
+	if len(edits) != 1 {
+		t.Fatalf("got %d edits, want 1", len(edits))
+	}
+	e := edits[0]
+	if e.ID != "UCE_1" || e.Editor.Login != "octocat" || !e.EditedAt.Equal(editedAt) || e.Diff != "new body" {
+		t.Errorf("unexpected edit: %+v", e)
+	}
+	if e.DeletedAt == nil || !e.DeletedAt.Equal(deletedAt) {
+		t.Errorf("DeletedAt = %v, want %v", e.DeletedAt, deletedAt)
+	}
+	if e.DeletedBy == nil || !e.DeletedBy.Ghost {
+		t.Errorf("DeletedBy = %+v, want the ghost user", e.DeletedBy)
+	}
+}
+
+// editsHandler serves userContentEdits pages of one edit each, numbered from
+// 1 to total, and records the cursors it was asked for.
+func editsHandler(t *testing.T, total int, cursors *[]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		cursor := req.Variables["cursor"]
+		*cursors = append(*cursors, cursor)
+
+		page := 1
+		if cursor != nil {
+			fmt.Sscanf(cursor.(string), "c%d", &page)
+			page++
+		}
+		fmt.Fprintf(w, `{"data":{"node":{"userContentEdits":{
+			"pageInfo":{"hasNextPage":%t,"endCursor":"c%d"},
+			"nodes":[{"id":"E%d","editedAt":"2024-01-01T00:00:00Z","diff":"v%d"}]
+		}}}}`, page < total, page, page, page)
+	}
+}
+
+func TestCollectEditsFollowsCursor(t *testing.T) {
+	var cursors []any
+	c := newTestClient(t, editsHandler(t, 3, &cursors))
+
+	var first userContentEdits
+	first.PageInfo.HasNextPage = true
+	first.PageInfo.EndCursor = "c0"
+
+	edits, err := c.collectEdits(context.Background(), "I_1", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range edits {
+		ids = append(ids, e.ID)
+	}
+	if fmt.Sprint(ids) != "[E1 E2 E3]" {
+		t.Errorf("got edits %v, want [E1 E2 E3]", ids)
+	}
+	if fmt.Sprint(cursors) != "[c0 c1 c2]" {
+		t.Errorf("got cursors %v, want [c0 c1 c2]", cursors)
+	}
+}
+
+func TestFetchEditsFromStart(t *testing.T) {
+	var cursors []any
+	c := newTestClient(t, editsHandler(t, 2, &cursors))
+
+	edits, err := c.fetchEdits(context.Background(), "PRRC_1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 2 || edits[0].ID != "E1" || edits[1].ID != "E2" {
+		t.Errorf("unexpected edits: %+v", edits)
+	}
+	if len(cursors) != 2 || cursors[0] != nil {
+		t.Errorf("got cursors %v, want a nil first cursor", cursors)
+	}
+}
+
+func TestParseDatabaseID(t *testing.T) {
+	str := func(s string) *githubv4.String {
+		v := githubv4.String(s)
+		return &v
+	}
+	tests := []struct {
+		name string
+		in   *githubv4.String
+		want int64
+	}{
+		{"missing", nil, 0},
+		{"small", str("42"), 42},
+		{"beyond 32 bits", str("2147483648"), 2147483648},
+		{"large", str("9007199254740993"), 9007199254740993},
+		{"malformed", str("abc"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDatabaseID(tt.in); got != tt.want {
+				t.Errorf("parseDatabaseID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertActor(t *testing.T) {
+	name := githubv4.String("The Octocat")
+	bot := &actorNode{TypeName: "Bot", Login: "dependabot", URL: "https://github.com/apps/dependabot"}
+	bot.Node.ID = "BOT_1"
+	user := &actorNode{TypeName: "User", Login: "octocat"}
+	user.User.Name = &name
+	org := &actorNode{TypeName: "Organization", Login: "github"}
+	org.Organization.Name = &name
+
+	tests := []struct {
+		name string
+		in   *actorNode
+		want Actor
+	}{
+		{"deleted", nil, Actor{Login: "ghost", Ghost: true}},
+		{"ghost", &actorNode{TypeName: "User", Login: "ghost"}, Actor{Login: "ghost", Type: "User", Ghost: true}},
+		{"bot", bot, Actor{ID: "BOT_1", Login: "dependabot", Type: "Bot", URL: "https://github.com/apps/dependabot"}},
+		{"mannequin", &actorNode{TypeName: "Mannequin", Login: "imported"}, Actor{Login: "imported", Type: "Mannequin"}},
+		{"user", user, Actor{Login: "octocat", Type: "User", Name: "The Octocat"}},
+		{"organization", org, Actor{Login: "github", Type: "Organization", Name: "The Octocat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertActor(tt.in); got != tt.want {
+				t.Errorf("convertActor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertMilestone(t *testing.T) {
+	desc := githubv4.String("First release")
+	due := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	open := convertMilestone(milestoneNode{ID: "M_1", Number: 1, Title: "v1", State: "OPEN", Description: &desc, DueOn: &githubv4.DateTime{Time: due}})
+	if open.Title != "v1" || open.Description != "First release" || open.State != "OPEN" {
+		t.Errorf("unexpected milestone: %+v", open)
+	}
+	if open.DueOn == nil || !open.DueOn.Equal(due) {
+		t.Errorf("DueOn = %v, want %v", open.DueOn, due)
+	}
+	if open.ClosedAt != nil {
+		t.Errorf("ClosedAt = %v, want nil", open.ClosedAt)
+	}
+
+	closed := convertMilestone(milestoneNode{Number: 2, State: "CLOSED", ClosedAt: &githubv4.DateTime{Time: due}})
+	if closed.DueOn != nil || closed.ClosedAt == nil || closed.Description != "" {
+		t.Errorf("unexpected milestone: %+v", closed)
+	}
+}
+
+func TestConvertUser(t *testing.T) {
+	name := githubv4.String("Mona")
+	got := convertUser(userNode{ID: "U_1", Login: "mona", Name: &name})
+	want := Actor{ID: "U_1", Login: "mona", Type: "User", Name: "Mona"}
+	if got != want {
+		t.Errorf("convertUser() = %+v, want %+v", got, want)
+	}
+	if got := convertUser(userNode{Login: "anon"}); got.Name != "" || got.Type != "User" {
+		t.Errorf("convertUser() = %+v, want a User without a name", got)
+	}
+}
+
+func TestCollectItemRefsFollowsCursor(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		queries = append(queries, req.Query)
+		if req.Variables["id"] != "I_1" || req.Variables["cursor"] != "c1" {
+			t.Errorf("unexpected variables: %v", req.Variables)
+		}
+		fmt.Fprint(w, `{"data":{"node":{"subIssues":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+			"nodes":[{"number":3,"title":"third","repository":{"nameWithOwner":"o/r"}}]
+		}}}}`)
+	}))
+
+	first := itemRefConnection{
+		PageInfo: pageInfo{HasNextPage: true, EndCursor: "c1"},
+		Nodes:    []itemRefNode{{Number: 1}, {Number: 2}},
+	}
+	refs, err := c.collectItemRefs(context.Background(), "I_1", first, func() itemRefPage { return &subIssuesQuery{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refs) != 3 || refs[2].Number != 3 || refs[2].Repository != "o/r" {
+		t.Errorf("unexpected refs: %+v", refs)
+	}
+	if len(queries) != 1 || !strings.Contains(queries[0], "subIssues(first: 100, after: $cursor)") {
+		t.Errorf("unexpected queries: %q", queries)
+	}
+}
+
+func TestConvertCrossReference(t *testing.T) {
+	var e crossReferencedEvent
+	e.Actor = &actorNode{TypeName: "User", Login: "octocat"}
+	e.WillCloseTarget = true
+	e.Source.TypeName = "PullRequest"
+	e.Source.PR = crossReferencingSource{Number: 7, Title: "Fix it", URL: "https://github.com/o/r/pull/7"}
+	e.Source.PR.Repository.NameWithOwner = "o/r"
+
+	event := convertCrossReference(e)
+	if event.Type != "cross-referenced" || event.Actor.Login != "octocat" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	details := event.Details.(map[string]any)
+	want := map[string]any{
+		"source_type":       "PullRequest",
+		"source_repository": "o/r",
+		"source_number":     7,
+		"source_title":      "Fix it",
+		"source_url":        "https://github.com/o/r/pull/7",
+		"will_close_target": true,
+	}
+	if fmt.Sprint(details) != fmt.Sprint(want) {
+		t.Errorf("details = %v, want %v", details, want)
+	}
+}
+
+func TestCollectIssueEventsFollowsCursor(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"node":{"timelineItems":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+			"nodes":[{
+				"__typename":"CrossReferencedEvent","id":"CRE_1",
+				"actor":{"__typename":"User","login":"octocat"},
+				"createdAt":"2024-01-01T00:00:00Z","willCloseTarget":false,
+				"source":{"__typename":"Issue","number":9,"title":"other","repository":{"nameWithOwner":"o/other"}}
+			},{"__typename":"UnknownEvent","id":"X_1"}]
+		}}}}`)
+	}))
+
+	var first issueTimelineConnection
+	first.PageInfo = pageInfo{HasNextPage: true, EndCursor: "c1"}
+	events, err := c.collectIssueEvents(context.Background(), "I_1", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1: %+v", len(events), events)
+	}
+	e := events[0]
+	if e.ID != "CRE_1" || e.Type != "cross-referenced" || e.Details.(map[string]any)["source_repository"] != "o/other" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
+
+func TestCanonicalDetails(t *testing.T) {
+	var issue, pr, none duplicateCanonical
+	issue.Issue = crossReferencingSource{Number: 5, URL: "https://github.com/o/r/issues/5"}
+	issue.Issue.Repository.NameWithOwner = "o/r"
+	pr.PR = crossReferencingSource{Number: 6, URL: "https://github.com/o/r/pull/6"}
+	pr.PR.Repository.NameWithOwner = "o/r"
+
+	if got := fmt.Sprint(canonicalDetails(issue)); got != "map[canonical_number:5 canonical_repository:o/r canonical_url:https://github.com/o/r/issues/5]" {
+		t.Errorf("issue canonical = %s", got)
+	}
+	if got := canonicalDetails(pr); got["canonical_number"] != 6 {
+		t.Errorf("PR canonical = %v", got)
+	}
+	if got := canonicalDetails(none); got != nil {
+		t.Errorf("missing canonical = %v, want nil", got)
+	}
+}
+
+func TestConvertSharedTimelineEvent(t *testing.T) {
+	reason := githubv4.String("RESOLVED")
+	var renamed, lockedWithReason, locked, duplicate sharedTimelineItem
+	renamed.RenamedTitleEvent.PreviousTitle = "old"
+	renamed.RenamedTitleEvent.CurrentTitle = "new"
+	lockedWithReason.LockedEvent.LockReason = &reason
+
+	tests := []struct {
+		typeName  string
+		item      sharedTimelineItem
+		wantType  string
+		wantExtra string
+	}{
+		{"RenamedTitleEvent", renamed, "renamed", "map[from:old to:new]"},
+		{"LockedEvent", lockedWithReason, "locked", "map[reason:RESOLVED]"},
+		{"LockedEvent", locked, "locked", "<nil>"},
+		{"MarkedAsDuplicateEvent", duplicate, "marked_as_duplicate", "<nil>"},
+	}
+	for _, tt := range tests {
+		event := convertSharedTimelineEvent(tt.typeName, tt.item)
+		if event == nil {
+			t.Errorf("%s: got no event", tt.typeName)
+			continue
+		}
+		if event.Type != tt.wantType || fmt.Sprint(event.Details) != tt.wantExtra {
+			t.Errorf("%s: got %s %v, want %s %s", tt.typeName, event.Type, event.Details, tt.wantType, tt.wantExtra)
+		}
+	}
+
+	if event := convertSharedTimelineEvent("SubscribedEvent", sharedTimelineItem{}); event != nil {
+		t.Errorf("unknown event type converted to %+v", event)
+	}
+}
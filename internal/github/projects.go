@@ -0,0 +1,485 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+type Project struct {
+	ID               string         `json:"id"`
+	URL              string         `json:"url"`
+	Number           int            `json:"number"`
+	Title            string         `json:"title"`
+	ShortDescription string         `json:"short_description,omitempty"`
+	Readme           string         `json:"readme,omitempty"`
+	Owner            string         `json:"owner"`
+	Public           bool           `json:"public"`
+	Closed           bool           `json:"closed"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	Fields           []ProjectField `json:"fields"`
+	Items            []ProjectItem  `json:"items"`
+}
+
+// ProjectField is a field definition. Options is set for single select
+// fields and Iterations for iteration fields.
+type ProjectField struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	DataType   string               `json:"data_type"`
+	Options    []ProjectFieldOption `json:"options,omitempty"`
+	Iterations []ProjectIteration   `json:"iterations,omitempty"`
+}
+
+type ProjectFieldOption struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type ProjectIteration struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"start_date"`
+	Duration  int    `json:"duration"`
+	Completed bool   `json:"completed"`
+}
+
+// ProjectItem is a card on a project. Repository and Number identify the
+// issue or pull request it tracks; draft issues carry their own title and
+// body instead.
+type ProjectItem struct {
+	ID          string              `json:"id"`
+	Type        string              `json:"type"`
+	IsArchived  bool                `json:"is_archived"`
+	Repository  string              `json:"repository,omitempty"`
+	Number      int                 `json:"number,omitempty"`
+	Title       string              `json:"title,omitempty"`
+	Body        string              `json:"body,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	FieldValues []ProjectFieldValue `json:"field_values"`
+}
+
+// ProjectFieldValue is the value an item holds for one field. Value is a
+// string for text, date, single select and iteration fields and a number
+// for number fields.
+type ProjectFieldValue struct {
+	Field       string `json:"field"`
+	Type        string `json:"type"`
+	Value       any    `json:"value"`
+	OptionID    string `json:"option_id,omitempty"`
+	IterationID string `json:"iteration_id,omitempty"`
+}
+
+// ProjectMembership is the view of a project item from the issue or pull
+// request it tracks.
+type ProjectMembership struct {
+	ItemID        string              `json:"item_id"`
+	ProjectID     string              `json:"project_id"`
+	ProjectNumber int                 `json:"project_number"`
+	ProjectTitle  string              `json:"project_title"`
+	IsArchived    bool                `json:"is_archived"`
+	FieldValues   []ProjectFieldValue `json:"field_values"`
+}
+
+type projectsQuery struct {
+	Repository struct {
+		ProjectsV2 struct {
+			PageInfo pageInfo
+			Nodes    []struct {
+				ID               githubv4.String
+				URL              githubv4.String
+				Number           githubv4.Int
+				Title            githubv4.String
+				ShortDescription *githubv4.String
+				Readme           *githubv4.String
+				Public           githubv4.Boolean
+				Closed           githubv4.Boolean
+				CreatedAt        githubv4.DateTime
+				UpdatedAt        githubv4.DateTime
+				Owner            struct {
+					User         struct{ Login githubv4.String } `graphql:"... on User"`
+					Organization struct{ Login githubv4.String } `graphql:"... on Organization"`
+				}
+				Fields struct {
+					Nodes []projectFieldNode
+				} `graphql:"fields(first: 50)"`
+			}
+		} `graphql:"projectsV2(first: 20, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type projectFieldNode struct {
+	Common struct {
+		ID       githubv4.String
+		Name     githubv4.String
+		DataType githubv4.String
+	} `graphql:"... on ProjectV2FieldCommon"`
+	SingleSelect struct {
+		Options []struct {
+			ID    githubv4.String
+			Name  githubv4.String
+			Color githubv4.String
+		}
+	} `graphql:"... on ProjectV2SingleSelectField"`
+	Iteration struct {
+		Configuration struct {
+			Iterations          []projectIterationNode
+			CompletedIterations []projectIterationNode
+		}
+	} `graphql:"... on ProjectV2IterationField"`
+}
+
+type projectIterationNode struct {
+	ID        githubv4.String
+	Title     githubv4.String
+	StartDate githubv4.String
+	Duration  githubv4.Int
+}
+
+type projectItemsQuery struct {
+	Node struct {
+		Project struct {
+			Items struct {
+				PageInfo pageInfo
+				Nodes    []struct {
+					ID         githubv4.String
+					Type       githubv4.String
+					IsArchived githubv4.Boolean
+					CreatedAt  githubv4.DateTime
+					UpdatedAt  githubv4.DateTime
+					Content    *struct {
+						Issue       projectContentNode `graphql:"... on Issue"`
+						PullRequest projectContentNode `graphql:"... on PullRequest"`
+						DraftIssue  struct {
+							Title githubv4.String
+							Body  githubv4.String
+						} `graphql:"... on DraftIssue"`
+					}
+					FieldValues projectFieldValues `graphql:"fieldValues(first: 50)"`
+				}
+			} `graphql:"items(first: 100, after: $cursor)"`
+		} `graphql:"... on ProjectV2"`
+	} `graphql:"node(id: $id)"`
+}
+
+type projectContentNode struct {
+	Number     githubv4.Int
+	Repository struct {
+		NameWithOwner githubv4.String
+	}
+}
+
+// projectMembershipsQuery fetches the project items of issues and pull
+// requests by node ID. It is kept out of the issue and pull request queries
+// because it needs the read:project scope.
+type projectMembershipsQuery struct {
+	Nodes []struct {
+		Issue struct {
+			ID           githubv4.String
+			ProjectItems projectItemsConnection `graphql:"projectItems(first: 20)"`
+		} `graphql:"... on Issue"`
+		PullRequest struct {
+			ID           githubv4.String
+			ProjectItems projectItemsConnection `graphql:"projectItems(first: 20)"`
+		} `graphql:"... on PullRequest"`
+	} `graphql:"nodes(ids: $ids)"`
+}
+
+// projectMembershipsBatch is the number of items looked up per query.
+const projectMembershipsBatch = 50
+
+// projectItemsConnection is the projectItems selection on issues and pull
+// requests.
+type projectItemsConnection struct {
+	Nodes []struct {
+		ID         githubv4.String
+		IsArchived githubv4.Boolean
+		Project    struct {
+			ID     githubv4.String
+			Number githubv4.Int
+			Title  githubv4.String
+		}
+		FieldValues projectFieldValues `graphql:"fieldValues(first: 20)"`
+	}
+}
+
+type projectFieldValues struct {
+	Nodes []struct {
+		TypeName string `graphql:"__typename"`
+		Text     struct {
+			Text  *githubv4.String
+			Field projectFieldName
+		} `graphql:"... on ProjectV2ItemFieldTextValue"`
+		Number struct {
+			Number *githubv4.Float
+			Field  projectFieldName
+		} `graphql:"... on ProjectV2ItemFieldNumberValue"`
+		Date struct {
+			Date  *githubv4.String
+			Field projectFieldName
+		} `graphql:"... on ProjectV2ItemFieldDateValue"`
+		SingleSelect struct {
+			Name     *githubv4.String
+			OptionID *githubv4.String
+			Field    projectFieldName
+		} `graphql:"... on ProjectV2ItemFieldSingleSelectValue"`
+		Iteration struct {
+			Title       githubv4.String
+			IterationID githubv4.String
+			Field       projectFieldName
+		} `graphql:"... on ProjectV2ItemFieldIterationValue"`
+	}
+}
+
+type projectFieldName struct {
+	Common struct {
+		Name githubv4.String
+	} `graphql:"... on ProjectV2FieldCommon"`
+}
+
+// FetchProjects returns the Projects (v2) linked to the repository with
+// their field definitions and every item. Project items cannot be ordered
+// by update time, so each sync fetches them in full.
+func (c *Client) FetchProjects(ctx context.Context, owner, repo string) ([]Project, error) {
+	var allProjects []Project
+	var cursor *githubv4.String
+
+	for {
+		var q projectsQuery
+		vars := map[string]any{
+			"owner":  githubv4.String(owner),
+			"repo":   githubv4.String(repo),
+			"cursor": cursor,
+		}
+
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Repository.ProjectsV2.Nodes {
+			project := Project{
+				ID:        string(node.ID),
+				URL:       string(node.URL),
+				Number:    int(node.Number),
+				Title:     string(node.Title),
+				Public:    bool(node.Public),
+				Closed:    bool(node.Closed),
+				CreatedAt: node.CreatedAt.Time,
+				UpdatedAt: node.UpdatedAt.Time,
+			}
+			if node.ShortDescription != nil {
+				project.ShortDescription = string(*node.ShortDescription)
+			}
+			if node.Readme != nil {
+				project.Readme = string(*node.Readme)
+			}
+			project.Owner = string(node.Owner.User.Login)
+			if project.Owner == "" {
+				project.Owner = string(node.Owner.Organization.Login)
+			}
+
+			for _, f := range node.Fields.Nodes {
+				project.Fields = append(project.Fields, convertProjectField(f))
+			}
+
+			items, err := c.fetchProjectItems(ctx, node.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch items of project %d: %w", project.Number, err)
+			}
+			project.Items = items
+
+			allProjects = append(allProjects, project)
+		}
+
+		if !q.Repository.ProjectsV2.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &q.Repository.ProjectsV2.PageInfo.EndCursor
+	}
+
+	return allProjects, nil
+}
+
+func (c *Client) fetchProjectItems(ctx context.Context, projectID githubv4.String) ([]ProjectItem, error) {
+	var items []ProjectItem
+	var cursor *githubv4.String
+
+	for {
+		var q projectItemsQuery
+		vars := map[string]any{
+			"id":     githubv4.ID(string(projectID)),
+			"cursor": cursor,
+		}
+
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Node.Project.Items.Nodes {
+			item := ProjectItem{
+				ID:          string(node.ID),
+				Type:        string(node.Type),
+				IsArchived:  bool(node.IsArchived),
+				CreatedAt:   node.CreatedAt.Time,
+				UpdatedAt:   node.UpdatedAt.Time,
+				FieldValues: convertProjectFieldValues(node.FieldValues),
+			}
+			if node.Content != nil {
+				switch item.Type {
+				case "ISSUE":
+					item.Repository = string(node.Content.Issue.Repository.NameWithOwner)
+					item.Number = int(node.Content.Issue.Number)
+				case "PULL_REQUEST":
+					item.Repository = string(node.Content.PullRequest.Repository.NameWithOwner)
+					item.Number = int(node.Content.PullRequest.Number)
+				case "DRAFT_ISSUE":
+					item.Title = string(node.Content.DraftIssue.Title)
+					item.Body = string(node.Content.DraftIssue.Body)
+				}
+			}
+			items = append(items, item)
+		}
+
+		if !q.Node.Project.Items.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &q.Node.Project.Items.PageInfo.EndCursor
+	}
+
+	return items, nil
+}
+
+func convertProjectField(f projectFieldNode) ProjectField {
+	field := ProjectField{
+		ID:       string(f.Common.ID),
+		Name:     string(f.Common.Name),
+		DataType: string(f.Common.DataType),
+	}
+	for _, o := range f.SingleSelect.Options {
+		field.Options = append(field.Options, ProjectFieldOption{
+			ID:    string(o.ID),
+			Name:  string(o.Name),
+			Color: string(o.Color),
+		})
+	}
+	for _, it := range f.Iteration.Configuration.Iterations {
+		field.Iterations = append(field.Iterations, convertProjectIteration(it, false))
+	}
+	for _, it := range f.Iteration.Configuration.CompletedIterations {
+		field.Iterations = append(field.Iterations, convertProjectIteration(it, true))
+	}
+	return field
+}
+
+func convertProjectIteration(it projectIterationNode, completed bool) ProjectIteration {
+	return ProjectIteration{
+		ID:        string(it.ID),
+		Title:     string(it.Title),
+		StartDate: string(it.StartDate),
+		Duration:  int(it.Duration),
+		Completed: completed,
+	}
+}
+
+// FetchProjectMemberships returns the project items of the issues and pull
+// requests with the given node IDs, keyed by node ID. Like FetchProjects, it
+// needs the read:project scope.
+func (c *Client) FetchProjectMemberships(ctx context.Context, ids []string) (map[string][]ProjectMembership, error) {
+	memberships := map[string][]ProjectMembership{}
+	for start := 0; start < len(ids); start += projectMembershipsBatch {
+		end := min(start+projectMembershipsBatch, len(ids))
+		batch := make([]githubv4.ID, 0, end-start)
+		for _, id := range ids[start:end] {
+			batch = append(batch, githubv4.ID(id))
+		}
+
+		var q projectMembershipsQuery
+		if err := c.gql.Query(ctx, &q, map[string]any{"ids": batch}); err != nil {
+			return nil, err
+		}
+		for _, n := range q.Nodes {
+			switch {
+			case n.Issue.ID != "":
+				memberships[string(n.Issue.ID)] = convertProjectMemberships(n.Issue.ProjectItems)
+			case n.PullRequest.ID != "":
+				memberships[string(n.PullRequest.ID)] = convertProjectMemberships(n.PullRequest.ProjectItems)
+			}
+		}
+	}
+	return memberships, nil
+}
+
+func convertProjectMemberships(conn projectItemsConnection) []ProjectMembership {
+	var memberships []ProjectMembership
+	for _, n := range conn.Nodes {
+		memberships = append(memberships, ProjectMembership{
+			ItemID:        string(n.ID),
+			ProjectID:     string(n.Project.ID),
+			ProjectNumber: int(n.Project.Number),
+			ProjectTitle:  string(n.Project.Title),
+			IsArchived:    bool(n.IsArchived),
+			FieldValues:   convertProjectFieldValues(n.FieldValues),
+		})
+	}
+	return memberships
+}
+
+// convertProjectFieldValues keeps the values of the field types listed in
+// the query; values of other types (labels, assignees, ...) duplicate data
+// already stored on the item.
+func convertProjectFieldValues(values projectFieldValues) []ProjectFieldValue {
+	var out []ProjectFieldValue
+	for _, v := range values.Nodes {
+		switch v.TypeName {
+		case "ProjectV2ItemFieldTextValue":
+			if v.Text.Text != nil {
+				out = append(out, ProjectFieldValue{
+					Field: string(v.Text.Field.Common.Name),
+					Type:  "text",
+					Value: string(*v.Text.Text),
+				})
+			}
+		case "ProjectV2ItemFieldNumberValue":
+			if v.Number.Number != nil {
+				out = append(out, ProjectFieldValue{
+					Field: string(v.Number.Field.Common.Name),
+					Type:  "number",
+					Value: float64(*v.Number.Number),
+				})
+			}
+		case "ProjectV2ItemFieldDateValue":
+			if v.Date.Date != nil {
+				out = append(out, ProjectFieldValue{
+					Field: string(v.Date.Field.Common.Name),
+					Type:  "date",
+					Value: string(*v.Date.Date),
+				})
+			}
+		case "ProjectV2ItemFieldSingleSelectValue":
+			value := ProjectFieldValue{
+				Field: string(v.SingleSelect.Field.Common.Name),
+				Type:  "single_select",
+			}
+			if v.SingleSelect.Name != nil {
+				value.Value = string(*v.SingleSelect.Name)
+			}
+			if v.SingleSelect.OptionID != nil {
+				value.OptionID = string(*v.SingleSelect.OptionID)
+			}
+			out = append(out, value)
+		case "ProjectV2ItemFieldIterationValue":
+			out = append(out, ProjectFieldValue{
+				Field:       string(v.Iteration.Field.Common.Name),
+				Type:        "iteration",
+				Value:       string(v.Iteration.Title),
+				IterationID: string(v.Iteration.IterationID),
+			})
+		}
+	}
+	return out
+}
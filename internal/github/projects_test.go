@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFetchProjectMemberships(t *testing.T) {
+	var batches []int
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				IDs []string `json:"ids"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		batches = append(batches, len(req.Variables.IDs))
+		if req.Variables.IDs[0] != "I_0" {
+			fmt.Fprint(w, `{"data":{"nodes":[]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"nodes":[{
+			"id":"I_0","projectItems":{"nodes":[{
+				"id":"PVTI_1","isArchived":false,
+				"project":{"id":"PVT_1","number":2,"title":"Roadmap"},
+				"fieldValues":{"nodes":[
+					{"__typename":"ProjectV2ItemFieldTextValue","text":"note","field":{"name":"Notes"}},
+					{"__typename":"ProjectV2ItemFieldNumberValue","number":3.5,"field":{"name":"Estimate"}},
+					{"__typename":"ProjectV2ItemFieldDateValue","date":null,"field":{"name":"Due"}},
+					{"__typename":"ProjectV2ItemFieldSingleSelectValue","name":"Done","optionId":"opt1","field":{"name":"Status"}},
+					{"__typename":"ProjectV2ItemFieldIterationValue","title":"Sprint 1","iterationId":"it1","field":{"name":"Sprint"}},
+					{"__typename":"ProjectV2ItemFieldLabelValue"}
+				]}
+			}]}
+		},{
+			"id":"PR_1","projectItems":{"nodes":[]}
+		}]}}`)
+	}))
+
+	ids := make([]string, projectMembershipsBatch+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("I_%d", i)
+	}
+	memberships, err := c.FetchProjectMemberships(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(batches) != fmt.Sprintf("[%d 1]", projectMembershipsBatch) {
+		t.Errorf("got batches %v", batches)
+	}
+
+	items := memberships["I_0"]
+	if len(items) != 1 || items[0].ProjectTitle != "Roadmap" || items[0].ProjectNumber != 2 {
+		t.Fatalf("unexpected memberships: %+v", items)
+	}
+	want := []ProjectFieldValue{
+		{Field: "Notes", Type: "text", Value: "note"},
+		{Field: "Estimate", Type: "number", Value: 3.5},
+		{Field: "Status", Type: "single_select", Value: "Done", OptionID: "opt1"},
+		{Field: "Sprint", Type: "iteration", Value: "Sprint 1", IterationID: "it1"},
+	}
+	if got := items[0].FieldValues; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got field values %+v, want %+v", got, want)
+	}
+	if _, ok := memberships["PR_1"]; !ok {
+		t.Errorf("missing entry for PR_1")
+	}
+}
@@ -2,40 +2,64 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/shurcooL/githubv4"
 )
 
 type PullRequest struct {
-	Number    int        `json:"number"`
-	Title     string     `json:"title"`
-	Body      string     `json:"body"`
-	State     string     `json:"state"`
-	Author    Actor      `json:"author"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	ClosedAt  *time.Time `json:"closed_at,omitempty"`
-	MergedAt  *time.Time `json:"merged_at,omitempty"`
-	Labels    []Label    `json:"labels"`
-	Comments  []Comment  `json:"comments"`
-	Reviews   []Review   `json:"reviews"`
-	Events    []Event    `json:"events"`
+	ID                string              `json:"id"`
+	DatabaseID        int64               `json:"database_id,omitempty"`
+	URL               string              `json:"url"`
+	Number            int                 `json:"number"`
+	Title             string              `json:"title"`
+	Body              string              `json:"body"`
+	State             string              `json:"state"`
+	Author            Actor               `json:"author"`
+	AuthorAssociation string              `json:"author_association"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+	ClosedAt          *time.Time          `json:"closed_at,omitempty"`
+	MergedAt          *time.Time          `json:"merged_at,omitempty"`
+	Labels            []Label             `json:"labels"`
+	Assignees         []Actor             `json:"assignees"`
+	Milestone         *Milestone          `json:"milestone,omitempty"`
+	ClosingIssues     []ItemRef           `json:"closing_issues,omitempty"`
+	ProjectItems      []ProjectMembership `json:"project_items,omitempty"`
+	Comments          []Comment           `json:"comments"`
+	Reviews           []Review            `json:"reviews"`
+	Events            []Event             `json:"events"`
+	Edits             []Edit              `json:"edits,omitempty"`
 }
 
 type ReviewComment struct {
-	Author    Actor     `json:"author"`
-	Body      string    `json:"body"`
-	Path      string    `json:"path"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                string     `json:"id"`
+	DatabaseID        int64      `json:"database_id,omitempty"`
+	URL               string     `json:"url"`
+	Author            Actor      `json:"author"`
+	AuthorAssociation string     `json:"author_association"`
+	Body              string     `json:"body"`
+	Path              string     `json:"path"`
+	CreatedAt         time.Time  `json:"created_at"`
+	LastEditedAt      *time.Time `json:"last_edited_at,omitempty"`
+	IsMinimized       bool       `json:"is_minimized"`
+	MinimizedReason   string     `json:"minimized_reason,omitempty"`
+	Edits             []Edit     `json:"edits,omitempty"`
 }
 
 type Review struct {
-	Author      Actor           `json:"author"`
-	Body        string          `json:"body"`
-	State       string          `json:"state"`
-	SubmittedAt time.Time       `json:"submitted_at"`
-	Comments    []ReviewComment `json:"comments,omitempty"`
+	ID                string          `json:"id"`
+	DatabaseID        int64           `json:"database_id,omitempty"`
+	URL               string          `json:"url"`
+	Author            Actor           `json:"author"`
+	AuthorAssociation string          `json:"author_association"`
+	Body              string          `json:"body"`
+	State             string          `json:"state"`
+	SubmittedAt       time.Time       `json:"submitted_at"`
+	LastEditedAt      *time.Time      `json:"last_edited_at,omitempty"`
+	Edits             []Edit          `json:"edits,omitempty"`
+	Comments          []ReviewComment `json:"comments,omitempty"`
 }
 
 type prQuery struct {
@@ -46,69 +70,73 @@ type prQuery struct {
 				EndCursor   githubv4.String
 			}
 			Nodes []struct {
-				Number    githubv4.Int
-				Title     githubv4.String
-				Body      githubv4.String
-				State     githubv4.String
-				CreatedAt githubv4.DateTime
-				UpdatedAt githubv4.DateTime
-				ClosedAt  *githubv4.DateTime
-				MergedAt  *githubv4.DateTime
-				Author    struct {
-					Login githubv4.String
-				}
-				Labels struct {
-					Nodes []struct {
-						Name  githubv4.String
-						Color githubv4.String
-					}
+				ID                githubv4.String
+				FullDatabaseID    *githubv4.String
+				URL               githubv4.String
+				Number            githubv4.Int
+				Title             githubv4.String
+				Body              githubv4.String
+				State             githubv4.String
+				CreatedAt         githubv4.DateTime
+				UpdatedAt         githubv4.DateTime
+				ClosedAt          *githubv4.DateTime
+				MergedAt          *githubv4.DateTime
+				Author            *actorNode
+				AuthorAssociation githubv4.String
+				UserContentEdits  userContentEdits `graphql:"userContentEdits(first: 20)"`
+				Labels            struct {
+					Nodes []labelNode
 				} `graphql:"labels(first: 50)"`
-				Comments struct {
-					Nodes []struct {
-						Author struct {
-							Login githubv4.String
-						}
-						Body      githubv4.String
-						CreatedAt githubv4.DateTime
-						UpdatedAt githubv4.DateTime
-					}
+				Assignees struct {
+					Nodes []userNode
+				} `graphql:"assignees(first: 20)"`
+				Milestone               *milestoneNode
+				ClosingIssuesReferences itemRefConnection `graphql:"closingIssuesReferences(first: 20)"`
+				Comments                struct {
+					Nodes []issueCommentNode
 				} `graphql:"comments(first: 50)"`
 				Reviews struct {
 					Nodes []struct {
-						Author struct {
-							Login githubv4.String
-						}
-						Body        githubv4.String
-						State       githubv4.String
-						SubmittedAt *githubv4.DateTime
-						Comments    struct {
+						ID                githubv4.String
+						FullDatabaseID    *githubv4.String
+						URL               githubv4.String
+						Author            *actorNode
+						AuthorAssociation githubv4.String
+						Body              githubv4.String
+						State             githubv4.String
+						SubmittedAt       *githubv4.DateTime
+						LastEditedAt      *githubv4.DateTime
+						Comments          struct {
 							Nodes []struct {
-								Author struct {
-									Login githubv4.String
-								}
-								Body      githubv4.String
-								Path      githubv4.String
-								CreatedAt githubv4.DateTime
+								ID                githubv4.String
+								FullDatabaseID    *githubv4.String
+								URL               githubv4.String
+								Author            *actorNode
+								AuthorAssociation githubv4.String
+								Body              githubv4.String
+								Path              githubv4.String
+								CreatedAt         githubv4.DateTime
+								LastEditedAt      *githubv4.DateTime
+								IsMinimized       githubv4.Boolean
+								MinimizedReason   *githubv4.String
 							}
 						} `graphql:"comments(first: 50)"`
 					}
 				} `graphql:"reviews(first: 50)"`
-				TimelineItems struct {
-					Nodes []prTimelineItem
-				} `graphql:"timelineItems(first: 50)"`
+				TimelineItems prTimelineConnection `graphql:"timelineItems(first: 50)"`
 			}
 		} `graphql:"pullRequests(first: 20, orderBy: {field: UPDATED_AT, direction: DESC}, after: $cursor)"`
 	} `graphql:"repository(owner: $owner, name: $repo)"`
 }
 
 type mergedEvent struct {
-	Actor     struct{ Login githubv4.String }
+	Actor     *actorNode
 	CreatedAt githubv4.DateTime
 }
 
 type reviewRequestedEvent struct {
-	Actor            struct{ Login githubv4.String }
-	CreatedAt        githubv4.DateTime
+	Actor             *actorNode
+	CreatedAt         githubv4.DateTime
 	RequestedReviewer struct {
 		User struct{ Login githubv4.String } `graphql:"... on User"`
 	}
@@ -116,24 +144,90 @@ type reviewRequestedEvent struct {
 
 type pullRequestCommit struct {
 	Commit struct {
-		Oid     githubv4.String
-		Message githubv4.String
-		Author  struct {
+		Oid           githubv4.String
+		Message       githubv4.String
+		CommittedDate githubv4.DateTime
+		Author        struct {
 			Name githubv4.String
 		}
 	}
 }
 
+type readyForReviewEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+}
+
+type convertToDraftEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+}
+
+type headRefForcePushedEvent struct {
+	Actor        *actorNode
+	CreatedAt    githubv4.DateTime
+	BeforeCommit *struct{ Oid githubv4.String }
+	AfterCommit  *struct{ Oid githubv4.String }
+	Ref          *struct{ Name githubv4.String }
+}
+
+type baseRefChangedEvent struct {
+	Actor           *actorNode
+	CreatedAt       githubv4.DateTime
+	PreviousRefName githubv4.String
+	CurrentRefName  githubv4.String
+}
+
+type reviewDismissedEvent struct {
+	Actor               *actorNode
+	CreatedAt           githubv4.DateTime
+	DismissalMessage    *githubv4.String
+	PreviousReviewState githubv4.String
+	Review              *struct {
+		ID     githubv4.String
+		Author *actorNode
+	}
+}
+
+type autoMergeEnabledEvent struct {
+	Actor     *actorNode
+	CreatedAt githubv4.DateTime
+	Enabler   *struct{ Login githubv4.String }
+}
+
+type autoMergeDisabledEvent struct {
+	Actor      *actorNode
+	CreatedAt  githubv4.DateTime
+	Disabler   *struct{ Login githubv4.String }
+	Reason     *githubv4.String
+	ReasonCode *githubv4.String
+}
+
+type deployedEvent struct {
+	Actor      *actorNode
+	CreatedAt  githubv4.DateTime
+	Deployment struct {
+		Environment *githubv4.String
+		State       *githubv4.String
+	}
+	Ref *struct{ Name githubv4.String }
+}
+
 type prTimelineItem struct {
-	TypeName             string               `graphql:"__typename"`
-	ClosedEvent          closedEvent          `graphql:"... on ClosedEvent"`
-	ReopenedEvent        reopenedEvent        `graphql:"... on ReopenedEvent"`
-	MergedEvent          mergedEvent          `graphql:"... on MergedEvent"`
-	LabeledEvent         labeledEvent         `graphql:"... on LabeledEvent"`
-	UnlabeledEvent       unlabeledEvent       `graphql:"... on UnlabeledEvent"`
-	AssignedEvent        assignedEvent        `graphql:"... on AssignedEvent"`
-	ReviewRequestedEvent reviewRequestedEvent `graphql:"... on ReviewRequestedEvent"`
-	PullRequestCommit    pullRequestCommit    `graphql:"... on PullRequestCommit"`
+	TypeName string       `graphql:"__typename"`
+	Node     nodeFragment `graphql:"... on Node"`
+	sharedTimelineItem
+	MergedEvent             mergedEvent             `graphql:"... on MergedEvent"`
+	ReviewRequestedEvent    reviewRequestedEvent    `graphql:"... on ReviewRequestedEvent"`
+	PullRequestCommit       pullRequestCommit       `graphql:"... on PullRequestCommit"`
+	ReadyForReviewEvent     readyForReviewEvent     `graphql:"... on ReadyForReviewEvent"`
+	ConvertToDraftEvent     convertToDraftEvent     `graphql:"... on ConvertToDraftEvent"`
+	HeadRefForcePushedEvent headRefForcePushedEvent `graphql:"... on HeadRefForcePushedEvent"`
+	BaseRefChangedEvent     baseRefChangedEvent     `graphql:"... on BaseRefChangedEvent"`
+	ReviewDismissedEvent    reviewDismissedEvent    `graphql:"... on ReviewDismissedEvent"`
+	AutoMergeEnabledEvent   autoMergeEnabledEvent   `graphql:"... on AutoMergeEnabledEvent"`
+	AutoMergeDisabledEvent  autoMergeDisabledEvent  `graphql:"... on AutoMergeDisabledEvent"`
+	DeployedEvent           deployedEvent           `graphql:"... on DeployedEvent"`
 }
 
 func (c *Client) FetchPullRequests(ctx context.Context, owner, repo string, since *time.Time) ([]PullRequest, error) {
@@ -159,15 +253,24 @@ func (c *Client) FetchPullRequests(ctx context.Context, owner, repo string, sinc
 			}
 
 			pr := PullRequest{
-				Number:    int(node.Number),
-				Title:     string(node.Title),
-				Body:      string(node.Body),
-				State:     string(node.State),
-				Author:    Actor{Login: string(node.Author.Login)},
-				CreatedAt: node.CreatedAt.Time,
-				UpdatedAt: node.UpdatedAt.Time,
+				ID:                string(node.ID),
+				URL:               string(node.URL),
+				Number:            int(node.Number),
+				Title:             string(node.Title),
+				Body:              string(node.Body),
+				State:             string(node.State),
+				Author:            convertActor(node.Author),
+				AuthorAssociation: string(node.AuthorAssociation),
+				CreatedAt:         node.CreatedAt.Time,
+				UpdatedAt:         node.UpdatedAt.Time,
 			}
 
+			edits, err := c.collectEdits(ctx, node.ID, node.UserContentEdits)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch edits of PR %d: %w", pr.Number, err)
+			}
+			pr.Edits = edits
+			pr.DatabaseID = parseDatabaseID(node.FullDatabaseID)
 			if node.ClosedAt != nil {
 				t := node.ClosedAt.Time
 				pr.ClosedAt = &t
@@ -178,46 +281,83 @@ func (c *Client) FetchPullRequests(ctx context.Context, owner, repo string, sinc
 			}
 
 			for _, l := range node.Labels.Nodes {
-				pr.Labels = append(pr.Labels, Label{
-					Name:  string(l.Name),
-					Color: string(l.Color),
-				})
+				pr.Labels = append(pr.Labels, convertLabel(l))
+			}
+
+			for _, a := range node.Assignees.Nodes {
+				pr.Assignees = append(pr.Assignees, convertUser(a))
+			}
+			if node.Milestone != nil {
+				m := convertMilestone(*node.Milestone)
+				pr.Milestone = &m
+			}
+			if pr.ClosingIssues, err = c.collectItemRefs(ctx, node.ID, node.ClosingIssuesReferences, func() itemRefPage { return &closingIssuesQuery{} }); err != nil {
+				return nil, fmt.Errorf("failed to fetch closing issues of PR %d: %w", pr.Number, err)
 			}
 
-			for _, c := range node.Comments.Nodes {
-				pr.Comments = append(pr.Comments, Comment{
-					Author:    Actor{Login: string(c.Author.Login)},
-					Body:      string(c.Body),
-					CreatedAt: c.CreatedAt.Time,
-					UpdatedAt: c.UpdatedAt.Time,
-				})
+			for _, n := range node.Comments.Nodes {
+				comment, err := c.fetchComment(ctx, n.commentNode)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch comment edits of PR %d: %w", pr.Number, err)
+				}
+				comment.DatabaseID = parseDatabaseID(n.FullDatabaseID)
+				pr.Comments = append(pr.Comments, comment)
 			}
 
 			for _, r := range node.Reviews.Nodes {
 				review := Review{
-					Author: Actor{Login: string(r.Author.Login)},
-					Body:   string(r.Body),
-					State:  string(r.State),
+					ID:                string(r.ID),
+					URL:               string(r.URL),
+					Author:            convertActor(r.Author),
+					AuthorAssociation: string(r.AuthorAssociation),
+					Body:              string(r.Body),
+					State:             string(r.State),
+					DatabaseID:        parseDatabaseID(r.FullDatabaseID),
 				}
 				if r.SubmittedAt != nil {
 					review.SubmittedAt = r.SubmittedAt.Time
 				}
-				for _, c := range r.Comments.Nodes {
-					review.Comments = append(review.Comments, ReviewComment{
-						Author:    Actor{Login: string(c.Author.Login)},
-						Body:      string(c.Body),
-						Path:      string(c.Path),
-						CreatedAt: c.CreatedAt.Time,
-					})
+				if r.LastEditedAt != nil {
+					t := r.LastEditedAt.Time
+					review.LastEditedAt = &t
+					if review.Edits, err = c.fetchEdits(ctx, r.ID, nil); err != nil {
+						return nil, fmt.Errorf("failed to fetch review edits of PR %d: %w", pr.Number, err)
+					}
+				}
+				for _, n := range r.Comments.Nodes {
+					rc := ReviewComment{
+						ID:                string(n.ID),
+						URL:               string(n.URL),
+						Author:            convertActor(n.Author),
+						AuthorAssociation: string(n.AuthorAssociation),
+						Body:              string(n.Body),
+						Path:              string(n.Path),
+						CreatedAt:         n.CreatedAt.Time,
+						IsMinimized:       bool(n.IsMinimized),
+						DatabaseID:        parseDatabaseID(n.FullDatabaseID),
+					}
+					if n.LastEditedAt != nil {
+						t := n.LastEditedAt.Time
+						rc.LastEditedAt = &t
+					}
+					if n.MinimizedReason != nil {
+						rc.MinimizedReason = string(*n.MinimizedReason)
+					}
+					// Review comment edits are left out of the query, which
+					// would otherwise exceed GitHub's node limit, and only
+					// fetched for comments that were edited.
+					if n.LastEditedAt != nil {
+						if rc.Edits, err = c.fetchEdits(ctx, n.ID, nil); err != nil {
+							return nil, fmt.Errorf("failed to fetch review comment edits of PR %d: %w", pr.Number, err)
+						}
+					}
+					review.Comments = append(review.Comments, rc)
 				}
 				pr.Reviews = append(pr.Reviews, review)
 			}
 
-			for _, ti := range node.TimelineItems.Nodes {
-				event := convertPRTimelineEvent(ti)
-				if event != nil {
-					pr.Events = append(pr.Events, *event)
-				}
+			if pr.Events, err = c.collectPREvents(ctx, node.ID, node.TimelineItems); err != nil {
+				return nil, fmt.Errorf("failed to fetch timeline of PR %d: %w", pr.Number, err)
 			}
 
 			allPRs = append(allPRs, pr)
@@ -239,51 +379,60 @@ func (c *Client) FetchPullRequests(ctx context.Context, owner, repo string, sinc
 	return allPRs, nil
 }
 
-func convertPRTimelineEvent(ti prTimelineItem) *Event {
-	switch ti.TypeName {
-	case "ClosedEvent":
-		return &Event{
-			Type:      "closed",
-			Actor:     Actor{Login: string(ti.ClosedEvent.Actor.Login)},
-			CreatedAt: ti.ClosedEvent.CreatedAt.Time,
+type prTimelineConnection struct {
+	PageInfo pageInfo
+	Nodes    []prTimelineItem
+}
+
+// prTimelineQuery fetches a further page of the timeline of a pull request.
+type prTimelineQuery struct {
+	Node struct {
+		PullRequest struct {
+			TimelineItems prTimelineConnection `graphql:"timelineItems(first: 100, after: $cursor)"`
+		} `graphql:"... on PullRequest"`
+	} `graphql:"node(id: $id)"`
+}
+
+// collectPREvents converts the first page of the timeline of a pull request
+// and follows the cursor until every event has been fetched.
+func (c *Client) collectPREvents(ctx context.Context, id githubv4.String, conn prTimelineConnection) ([]Event, error) {
+	var events []Event
+	for {
+		for _, ti := range conn.Nodes {
+			event := convertPRTimelineEvent(ti)
+			if event != nil {
+				event.ID = string(ti.Node.ID)
+				events = append(events, *event)
+			}
 		}
-	case "ReopenedEvent":
-		return &Event{
-			Type:      "reopened",
-			Actor:     Actor{Login: string(ti.ReopenedEvent.Actor.Login)},
-			CreatedAt: ti.ReopenedEvent.CreatedAt.Time,
+
+		if !conn.PageInfo.HasNextPage {
+			return events, nil
 		}
+		var q prTimelineQuery
+		vars := map[string]any{
+			"id":     githubv4.ID(string(id)),
+			"cursor": conn.PageInfo.EndCursor,
+		}
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+		conn = q.Node.PullRequest.TimelineItems
+	}
+}
+
+func convertPRTimelineEvent(ti prTimelineItem) *Event {
+	switch ti.TypeName {
 	case "MergedEvent":
 		return &Event{
 			Type:      "merged",
-			Actor:     Actor{Login: string(ti.MergedEvent.Actor.Login)},
+			Actor:     convertActor(ti.MergedEvent.Actor),
 			CreatedAt: ti.MergedEvent.CreatedAt.Time,
 		}
-	case "LabeledEvent":
-		return &Event{
-			Type:      "labeled",
-			Actor:     Actor{Login: string(ti.LabeledEvent.Actor.Login)},
-			CreatedAt: ti.LabeledEvent.CreatedAt.Time,
-			Details:   map[string]string{"label": string(ti.LabeledEvent.Label.Name)},
-		}
-	case "UnlabeledEvent":
-		return &Event{
-			Type:      "unlabeled",
-			Actor:     Actor{Login: string(ti.UnlabeledEvent.Actor.Login)},
-			CreatedAt: ti.UnlabeledEvent.CreatedAt.Time,
-			Details:   map[string]string{"label": string(ti.UnlabeledEvent.Label.Name)},
-		}
-	case "AssignedEvent":
-		return &Event{
-			Type:      "assigned",
-			Actor:     Actor{Login: string(ti.AssignedEvent.Actor.Login)},
-			CreatedAt: ti.AssignedEvent.CreatedAt.Time,
-			Details:   map[string]string{"assignee": string(ti.AssignedEvent.Assignee.User.Login)},
-		}
 	case "ReviewRequestedEvent":
 		return &Event{
 			Type:      "review_requested",
-			Actor:     Actor{Login: string(ti.ReviewRequestedEvent.Actor.Login)},
+			Actor:     convertActor(ti.ReviewRequestedEvent.Actor),
 			CreatedAt: ti.ReviewRequestedEvent.CreatedAt.Time,
 			Details:   map[string]string{"reviewer": string(ti.ReviewRequestedEvent.RequestedReviewer.User.Login)},
 		}
@@ -291,12 +440,115 @@ func convertPRTimelineEvent(ti prTimelineItem) *Event {
 		return &Event{
 			Type:      "commit",
 			Actor:     Actor{Login: string(ti.PullRequestCommit.Commit.Author.Name)},
+			CreatedAt: ti.PullRequestCommit.Commit.CommittedDate.Time,
 			Details: map[string]string{
 				"sha":     string(ti.PullRequestCommit.Commit.Oid),
 				"message": string(ti.PullRequestCommit.Commit.Message),
 			},
 		}
+	case "ReadyForReviewEvent":
+		return &Event{
+			Type:      "ready_for_review",
+			Actor:     convertActor(ti.ReadyForReviewEvent.Actor),
+			CreatedAt: ti.ReadyForReviewEvent.CreatedAt.Time,
+		}
+	case "ConvertToDraftEvent":
+		return &Event{
+			Type:      "convert_to_draft",
+			Actor:     convertActor(ti.ConvertToDraftEvent.Actor),
+			CreatedAt: ti.ConvertToDraftEvent.CreatedAt.Time,
+		}
+	case "HeadRefForcePushedEvent":
+		e := ti.HeadRefForcePushedEvent
+		details := map[string]string{}
+		if e.BeforeCommit != nil {
+			details["before"] = string(e.BeforeCommit.Oid)
+		}
+		if e.AfterCommit != nil {
+			details["after"] = string(e.AfterCommit.Oid)
+		}
+		if e.Ref != nil {
+			details["ref"] = string(e.Ref.Name)
+		}
+		return &Event{
+			Type:      "head_ref_force_pushed",
+			Actor:     convertActor(e.Actor),
+			CreatedAt: e.CreatedAt.Time,
+			Details:   details,
+		}
+	case "BaseRefChangedEvent":
+		return &Event{
+			Type:      "base_ref_changed",
+			Actor:     convertActor(ti.BaseRefChangedEvent.Actor),
+			CreatedAt: ti.BaseRefChangedEvent.CreatedAt.Time,
+			Details: map[string]string{
+				"from": string(ti.BaseRefChangedEvent.PreviousRefName),
+				"to":   string(ti.BaseRefChangedEvent.CurrentRefName),
+			},
+		}
+	case "ReviewDismissedEvent":
+		e := ti.ReviewDismissedEvent
+		details := map[string]string{"previous_review_state": string(e.PreviousReviewState)}
+		if e.DismissalMessage != nil {
+			details["message"] = string(*e.DismissalMessage)
+		}
+		if e.Review != nil {
+			details["review_id"] = string(e.Review.ID)
+			details["reviewer"] = convertActor(e.Review.Author).Login
+		}
+		return &Event{
+			Type:      "review_dismissed",
+			Actor:     convertActor(e.Actor),
+			CreatedAt: e.CreatedAt.Time,
+			Details:   details,
+		}
+	case "AutoMergeEnabledEvent":
+		event := &Event{
+			Type:      "auto_merge_enabled",
+			Actor:     convertActor(ti.AutoMergeEnabledEvent.Actor),
+			CreatedAt: ti.AutoMergeEnabledEvent.CreatedAt.Time,
+		}
+		if ti.AutoMergeEnabledEvent.Enabler != nil {
+			event.Details = map[string]string{"enabler": string(ti.AutoMergeEnabledEvent.Enabler.Login)}
+		}
+		return event
+	case "AutoMergeDisabledEvent":
+		e := ti.AutoMergeDisabledEvent
+		details := map[string]string{}
+		if e.Disabler != nil {
+			details["disabler"] = string(e.Disabler.Login)
+		}
+		if e.Reason != nil {
+			details["reason"] = string(*e.Reason)
+		}
+		if e.ReasonCode != nil {
+			details["reason_code"] = string(*e.ReasonCode)
+		}
+		return &Event{
+			Type:      "auto_merge_disabled",
+			Actor:     convertActor(e.Actor),
+			CreatedAt: e.CreatedAt.Time,
+			Details:   details,
+		}
+	case "DeployedEvent":
+		e := ti.DeployedEvent
+		details := map[string]string{}
+		if e.Deployment.Environment != nil {
+			details["environment"] = string(*e.Deployment.Environment)
+		}
+		if e.Deployment.State != nil {
+			details["state"] = string(*e.Deployment.State)
+		}
+		if e.Ref != nil {
+			details["ref"] = string(e.Ref.Name)
+		}
+		return &Event{
+			Type:      "deployed",
+			Actor:     convertActor(e.Actor),
+			CreatedAt: e.CreatedAt.Time,
+			Details:   details,
+		}
 	default:
-		return nil
+		return convertSharedTimelineEvent(ti.TypeName, ti.sharedTimelineItem)
 	}
 }
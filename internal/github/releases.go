@@ -0,0 +1,152 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+type Release struct {
+	ID           string         `json:"id"`
+	DatabaseID   int64          `json:"database_id"`
+	URL          string         `json:"url"`
+	TagName      string         `json:"tag_name"`
+	TargetCommit string         `json:"target_commit"`
+	Name         string         `json:"name"`
+	Body         string         `json:"body"`
+	IsDraft      bool           `json:"is_draft"`
+	IsPrerelease bool           `json:"is_prerelease"`
+	IsLatest     bool           `json:"is_latest"`
+	Author       Actor          `json:"author"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	PublishedAt  *time.Time     `json:"published_at,omitempty"`
+	Assets       []ReleaseAsset `json:"assets"`
+}
+
+type ReleaseAsset struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	ContentType   string    `json:"content_type"`
+	Size          int       `json:"size"`
+	DownloadCount int       `json:"download_count"`
+	DownloadURL   string    `json:"download_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type releaseQuery struct {
+	Repository struct {
+		Releases struct {
+			PageInfo pageInfo
+			Nodes    []struct {
+				ID           githubv4.String
+				DatabaseID   *int64
+				URL          githubv4.String
+				TagName      githubv4.String
+				TagCommit    *struct{ Oid githubv4.String }
+				Name         *githubv4.String
+				Description  *githubv4.String
+				IsDraft      githubv4.Boolean
+				IsPrerelease githubv4.Boolean
+				IsLatest     githubv4.Boolean
+				Author       *userNode
+				CreatedAt    githubv4.DateTime
+				UpdatedAt    githubv4.DateTime
+				PublishedAt  *githubv4.DateTime
+				Assets       struct {
+					Nodes []struct {
+						ID            githubv4.String
+						Name          githubv4.String
+						ContentType   githubv4.String
+						Size          githubv4.Int
+						DownloadCount githubv4.Int
+						DownloadURL   githubv4.String `graphql:"downloadUrl"`
+						CreatedAt     githubv4.DateTime
+						UpdatedAt     githubv4.DateTime
+					}
+				} `graphql:"releaseAssets(first: 100)"`
+			}
+		} `graphql:"releases(first: 50, orderBy: {field: CREATED_AT, direction: DESC}, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+// FetchReleases returns every release of the repository. There is no
+// since filter: asset download counts change without touching the
+// release's update time, so the whole list is refreshed on each sync.
+func (c *Client) FetchReleases(ctx context.Context, owner, repo string) ([]Release, error) {
+	var allReleases []Release
+	var cursor *githubv4.String
+
+	for {
+		var q releaseQuery
+		vars := map[string]any{
+			"owner":  githubv4.String(owner),
+			"repo":   githubv4.String(repo),
+			"cursor": cursor,
+		}
+
+		if err := c.gql.Query(ctx, &q, vars); err != nil {
+			return nil, err
+		}
+
+		for _, node := range q.Repository.Releases.Nodes {
+			release := Release{
+				ID:           string(node.ID),
+				URL:          string(node.URL),
+				TagName:      string(node.TagName),
+				IsDraft:      bool(node.IsDraft),
+				IsPrerelease: bool(node.IsPrerelease),
+				IsLatest:     bool(node.IsLatest),
+				CreatedAt:    node.CreatedAt.Time,
+				UpdatedAt:    node.UpdatedAt.Time,
+			}
+			if node.DatabaseID != nil {
+				release.DatabaseID = *node.DatabaseID
+			}
+			// Release.author is User-typed, so a deleted author comes back
+			// as null rather than as the ghost account.
+			if node.Author != nil {
+				release.Author = convertUser(*node.Author)
+			} else {
+				release.Author = convertActor(nil)
+			}
+			if node.TagCommit != nil {
+				release.TargetCommit = string(node.TagCommit.Oid)
+			}
+			if node.Name != nil {
+				release.Name = string(*node.Name)
+			}
+			if node.Description != nil {
+				release.Body = string(*node.Description)
+			}
+			if node.PublishedAt != nil {
+				t := node.PublishedAt.Time
+				release.PublishedAt = &t
+			}
+
+			for _, a := range node.Assets.Nodes {
+				release.Assets = append(release.Assets, ReleaseAsset{
+					ID:            string(a.ID),
+					Name:          string(a.Name),
+					ContentType:   string(a.ContentType),
+					Size:          int(a.Size),
+					DownloadCount: int(a.DownloadCount),
+					DownloadURL:   string(a.DownloadURL),
+					CreatedAt:     a.CreatedAt.Time,
+					UpdatedAt:     a.UpdatedAt.Time,
+				})
+			}
+
+			allReleases = append(allReleases, release)
+		}
+
+		if !q.Repository.Releases.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &q.Repository.Releases.PageInfo.EndCursor
+	}
+
+	return allReleases, nil
+}
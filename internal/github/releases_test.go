@@ -0,0 +1,44 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestFetchReleases(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"repository":{"releases":{
+			"pageInfo":{"hasNextPage":false},
+			"nodes":[{
+				"id":"RE_1","databaseId":12,"tagName":"v1.0.0","tagCommit":{"oid":"abc"},
+				"name":"First","author":{"id":"U_1","login":"mona","name":"Mona"},
+				"releaseAssets":{"nodes":[{"name":"bin.tar.gz","size":10,"downloadCount":3}]}
+			},{
+				"id":"RE_2","tagName":"v0.1.0","author":null,"releaseAssets":{"nodes":[]}
+			}]
+		}}}}`)
+	}))
+
+	releases, err := c.FetchReleases(context.Background(), "o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("got %d releases, want 2", len(releases))
+	}
+	first := releases[0]
+	if first.DatabaseID != 12 || first.TargetCommit != "abc" || first.Name != "First" {
+		t.Errorf("unexpected release: %+v", first)
+	}
+	if want := (Actor{ID: "U_1", Login: "mona", Type: "User", Name: "Mona"}); first.Author != want {
+		t.Errorf("Author = %+v, want %+v", first.Author, want)
+	}
+	if len(first.Assets) != 1 || first.Assets[0].DownloadCount != 3 {
+		t.Errorf("Assets = %+v", first.Assets)
+	}
+	if !releases[1].Author.Ghost {
+		t.Errorf("Author = %+v, want the ghost user", releases[1].Author)
+	}
+}
@@ -0,0 +1,185 @@
+package github
+
+import (
+	"context"
+	"time"
+
+	"github.com/shurcooL/githubv4"
+)
+
+type Repository struct {
+	ID                   string      `json:"id"`
+	URL                  string      `json:"url"`
+	NameWithOwner        string      `json:"name_with_owner"`
+	Description          string      `json:"description"`
+	HomepageURL          string      `json:"homepage_url,omitempty"`
+	Topics               []string    `json:"topics"`
+	DefaultBranch        string      `json:"default_branch"`
+	Visibility           string      `json:"visibility"`
+	IsArchived           bool        `json:"is_archived"`
+	IsFork               bool        `json:"is_fork"`
+	License              *License    `json:"license,omitempty"`
+	StargazerCount       int         `json:"stargazer_count"`
+	ForkCount            int         `json:"fork_count"`
+	WatcherCount         int         `json:"watcher_count"`
+	OpenIssueCount       int         `json:"open_issue_count"`
+	ClosedIssueCount     int         `json:"closed_issue_count"`
+	OpenPullRequestCount int         `json:"open_pull_request_count"`
+	DiscussionCount      int         `json:"discussion_count"`
+	CreatedAt            time.Time   `json:"created_at"`
+	UpdatedAt            time.Time   `json:"updated_at"`
+	PushedAt             *time.Time  `json:"pushed_at,omitempty"`
+	Labels               []Label     `json:"labels"`
+	Milestones           []Milestone `json:"milestones"`
+}
+
+type License struct {
+	Key    string `json:"key"`
+	Name   string `json:"name"`
+	SpdxID string `json:"spdx_id,omitempty"`
+}
+
+type repositoryQuery struct {
+	Repository struct {
+		ID               githubv4.String
+		URL              githubv4.String
+		NameWithOwner    githubv4.String
+		Description      *githubv4.String
+		HomepageURL      *githubv4.String
+		Visibility       githubv4.String
+		IsArchived       githubv4.Boolean
+		IsFork           githubv4.Boolean
+		StargazerCount   githubv4.Int
+		ForkCount        githubv4.Int
+		CreatedAt        githubv4.DateTime
+		UpdatedAt        githubv4.DateTime
+		PushedAt         *githubv4.DateTime
+		DefaultBranchRef *struct {
+			Name githubv4.String
+		}
+		LicenseInfo *struct {
+			Key    githubv4.String
+			Name   githubv4.String
+			SpdxID *githubv4.String `graphql:"spdxId"`
+		}
+		RepositoryTopics struct {
+			Nodes []struct {
+				Topic struct {
+					Name githubv4.String
+				}
+			}
+		} `graphql:"repositoryTopics(first: 100)"`
+		Watchers         struct{ TotalCount githubv4.Int }
+		OpenIssues       struct{ TotalCount githubv4.Int } `graphql:"openIssues: issues(states: OPEN)"`
+		ClosedIssues     struct{ TotalCount githubv4.Int } `graphql:"closedIssues: issues(states: CLOSED)"`
+		OpenPullRequests struct{ TotalCount githubv4.Int } `graphql:"openPullRequests: pullRequests(states: OPEN)"`
+		Discussions      struct{ TotalCount githubv4.Int }
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type repositoryLabelsQuery struct {
+	Repository struct {
+		Labels struct {
+			PageInfo pageInfo
+			Nodes    []labelNode
+		} `graphql:"labels(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+type repositoryMilestonesQuery struct {
+	Repository struct {
+		Milestones struct {
+			PageInfo pageInfo
+			Nodes    []milestoneNode
+		} `graphql:"milestones(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $repo)"`
+}
+
+func (c *Client) FetchRepository(ctx context.Context, owner, repo string) (*Repository, error) {
+	var q repositoryQuery
+	vars := map[string]any{
+		"owner": githubv4.String(owner),
+		"repo":  githubv4.String(repo),
+	}
+	if err := c.gql.Query(ctx, &q, vars); err != nil {
+		return nil, err
+	}
+
+	r := q.Repository
+	repository := &Repository{
+		ID:                   string(r.ID),
+		URL:                  string(r.URL),
+		NameWithOwner:        string(r.NameWithOwner),
+		Visibility:           string(r.Visibility),
+		IsArchived:           bool(r.IsArchived),
+		IsFork:               bool(r.IsFork),
+		StargazerCount:       int(r.StargazerCount),
+		ForkCount:            int(r.ForkCount),
+		WatcherCount:         int(r.Watchers.TotalCount),
+		OpenIssueCount:       int(r.OpenIssues.TotalCount),
+		ClosedIssueCount:     int(r.ClosedIssues.TotalCount),
+		OpenPullRequestCount: int(r.OpenPullRequests.TotalCount),
+		DiscussionCount:      int(r.Discussions.TotalCount),
+		CreatedAt:            r.CreatedAt.Time,
+		UpdatedAt:            r.UpdatedAt.Time,
+	}
+	if r.Description != nil {
+		repository.Description = string(*r.Description)
+	}
+	if r.HomepageURL != nil {
+		repository.HomepageURL = string(*r.HomepageURL)
+	}
+	if r.PushedAt != nil {
+		t := r.PushedAt.Time
+		repository.PushedAt = &t
+	}
+	if r.DefaultBranchRef != nil {
+		repository.DefaultBranch = string(r.DefaultBranchRef.Name)
+	}
+	if r.LicenseInfo != nil {
+		repository.License = &License{
+			Key:  string(r.LicenseInfo.Key),
+			Name: string(r.LicenseInfo.Name),
+		}
+		if r.LicenseInfo.SpdxID != nil {
+			repository.License.SpdxID = string(*r.LicenseInfo.SpdxID)
+		}
+	}
+	for _, t := range r.RepositoryTopics.Nodes {
+		repository.Topics = append(repository.Topics, string(t.Topic.Name))
+	}
+
+	var cursor *githubv4.String
+	for {
+		var lq repositoryLabelsQuery
+		vars["cursor"] = cursor
+		if err := c.gql.Query(ctx, &lq, vars); err != nil {
+			return nil, err
+		}
+		for _, l := range lq.Repository.Labels.Nodes {
+			repository.Labels = append(repository.Labels, convertLabel(l))
+		}
+		if !lq.Repository.Labels.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &lq.Repository.Labels.PageInfo.EndCursor
+	}
+
+	cursor = nil
+	for {
+		var mq repositoryMilestonesQuery
+		vars["cursor"] = cursor
+		if err := c.gql.Query(ctx, &mq, vars); err != nil {
+			return nil, err
+		}
+		for _, m := range mq.Repository.Milestones.Nodes {
+			repository.Milestones = append(repository.Milestones, convertMilestone(m))
+		}
+		if !mq.Repository.Milestones.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &mq.Repository.Milestones.PageInfo.EndCursor
+	}
+
+	return repository, nil
+}
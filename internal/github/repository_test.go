@@ -0,0 +1,64 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestFetchRepository(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		switch {
+		case strings.Contains(req.Query, "labels(first: 100"):
+			if req.Variables["cursor"] == nil {
+				fmt.Fprint(w, `{"data":{"repository":{"labels":{"pageInfo":{"hasNextPage":true,"endCursor":"l1"},"nodes":[{"name":"bug"}]}}}}`)
+			} else {
+				fmt.Fprint(w, `{"data":{"repository":{"labels":{"pageInfo":{"hasNextPage":false},"nodes":[{"name":"docs"}]}}}}`)
+			}
+		case strings.Contains(req.Query, "milestones(first: 100"):
+			fmt.Fprint(w, `{"data":{"repository":{"milestones":{"pageInfo":{"hasNextPage":false},"nodes":[{"number":1,"title":"v1","state":"OPEN"}]}}}}`)
+		default:
+			fmt.Fprint(w, `{"data":{"repository":{
+				"nameWithOwner":"o/r","description":null,"visibility":"PUBLIC",
+				"defaultBranchRef":{"name":"main"},
+				"licenseInfo":{"key":"mit","name":"MIT License","spdxId":"MIT"},
+				"repositoryTopics":{"nodes":[{"topic":{"name":"cli"}}]},
+				"watchers":{"totalCount":3},"openIssues":{"totalCount":4},"closedIssues":{"totalCount":5},
+				"openPullRequests":{"totalCount":6},"discussions":{"totalCount":7}
+			}}}`)
+		}
+	}))
+
+	repo, err := c.FetchRepository(context.Background(), "o", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.NameWithOwner != "o/r" || repo.DefaultBranch != "main" || repo.Description != "" {
+		t.Errorf("unexpected repository: %+v", repo)
+	}
+	if repo.License == nil || repo.License.SpdxID != "MIT" {
+		t.Errorf("License = %+v", repo.License)
+	}
+	if fmt.Sprint(repo.Topics) != "[cli]" {
+		t.Errorf("Topics = %v", repo.Topics)
+	}
+	if repo.WatcherCount != 3 || repo.OpenIssueCount != 4 || repo.ClosedIssueCount != 5 || repo.OpenPullRequestCount != 6 || repo.DiscussionCount != 7 {
+		t.Errorf("unexpected counts: %+v", repo)
+	}
+	if len(repo.Labels) != 2 || repo.Labels[0].Name != "bug" || repo.Labels[1].Name != "docs" {
+		t.Errorf("Labels = %+v", repo.Labels)
+	}
+	if len(repo.Milestones) != 1 || repo.Milestones[0].Title != "v1" {
+		t.Errorf("Milestones = %+v", repo.Milestones)
+	}
+}
@@ -0,0 +1,98 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// restPerPage is the largest page size the REST API accepts.
+const restPerPage = 100
+
+// restMaxAttempts is how many times a rate-limited REST request is tried
+// before its error is returned.
+const restMaxAttempts = 5
+
+// ErrNotFound is returned by REST requests for objects that do not exist,
+// e.g. a workflow run that has been deleted.
+var ErrNotFound = errors.New("not found")
+
+// restGet fetches a REST API path and decodes the JSON response into v.
+// It is used for data the GraphQL schema does not expose. Requests that hit
+// a primary or secondary rate limit are retried once the limit allows.
+func (c *Client) restGet(ctx context.Context, path string, query url.Values, v any) error {
+	u := c.restURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusOK {
+			defer resp.Body.Close()
+			return json.NewDecoder(resp.Body).Decode(v)
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("GET %s: %w", path, ErrNotFound)
+		}
+		wait, limited := rateLimitWait(resp, body, attempt, time.Now())
+		if !limited || attempt == restMaxAttempts {
+			return fmt.Errorf("GET %s: %s: %s", path, resp.Status, body)
+		}
+
+		fmt.Printf("  Rate limited, retrying in %s\n", wait.Round(time.Second))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimitWait reports whether a failed response was rate limited and how
+// long to wait before the given attempt is retried. It follows GitHub's
+// guidance: honour Retry-After, then X-RateLimit-Reset once the primary
+// limit is used up, and otherwise back off exponentially from a minute.
+func rateLimitWait(resp *http.Response, body []byte, attempt int, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if secs, err := strconv.Atoi(s); err == nil {
+			return time.Duration(secs) * time.Second, true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0) + time.Second, true
+		}
+	}
+	// A 403 without rate limit headers is only a secondary limit if the
+	// message says so; otherwise it is a permission error.
+	if resp.StatusCode == http.StatusForbidden && !bytes.Contains(bytes.ToLower(body), []byte("rate limit")) {
+		return 0, false
+	}
+	return time.Minute << (attempt - 1), true
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name        string
+		status      int
+		headers     map[string]string
+		body        string
+		attempt     int
+		wantWait    time.Duration
+		wantLimited bool
+	}{
+		{"ok", http.StatusOK, nil, "", 1, 0, false},
+		{"server error", http.StatusBadGateway, nil, "", 1, 0, false},
+		{"retry after", http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}, "", 1, 30 * time.Second, true},
+		{"retry after on 403", http.StatusForbidden, map[string]string{"Retry-After": "5"}, "", 1, 5 * time.Second, true},
+		{"primary limit", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Unix()+120, 10)}, "", 1, 121 * time.Second, true},
+		{"primary limit already reset", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Unix()-10, 10)}, "", 1, time.Second, true},
+		{"secondary limit", http.StatusForbidden, nil, `{"message":"You have exceeded a secondary rate limit."}`, 1, time.Minute, true},
+		{"secondary limit backs off", http.StatusForbidden, nil, `{"message":"You have exceeded a secondary rate limit."}`, 3, 4 * time.Minute, true},
+		{"429 without headers", http.StatusTooManyRequests, nil, "", 2, 2 * time.Minute, true},
+		{"permission error", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "4999"}, `{"message":"Resource not accessible by integration"}`, 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			wait, limited := rateLimitWait(resp, []byte(tt.body), tt.attempt, now)
+			if wait != tt.wantWait || limited != tt.wantLimited {
+				t.Errorf("rateLimitWait() = %s, %v, want %s, %v", wait, limited, tt.wantWait, tt.wantLimited)
+			}
+		})
+	}
+}
+
+func TestRestGetRetriesRateLimits(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"total_count":7}`)
+	}))
+
+	var resp workflowRunsResponse
+	if err := c.restGet(context.Background(), "/runs", nil, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 || resp.TotalCount != 7 {
+		t.Errorf("got %d requests and total %d, want 3 and 7", requests, resp.TotalCount)
+	}
+}
+
+func TestRestGetGivesUp(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	var resp workflowRunsResponse
+	if err := c.restGet(context.Background(), "/runs", nil, &resp); err == nil {
+		t.Fatal("got no error")
+	}
+	if requests != restMaxAttempts {
+		t.Errorf("got %d requests, want %d", requests, restMaxAttempts)
+	}
+}
+
+func TestRestGetErrors(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, `{"message":"Must have admin rights"}`, http.StatusForbidden)
+	}))
+
+	var resp workflowRunsResponse
+	if err := c.restGet(context.Background(), "/missing", nil, &resp); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+	if err := c.restGet(context.Background(), "/forbidden", nil, &resp); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want a permission error", err)
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want 2 without retries", requests)
+	}
+}
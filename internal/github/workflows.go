@@ -0,0 +1,329 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type WorkflowRun struct {
+	ID              int64         `json:"id"`
+	URL             string        `json:"url"`
+	Name            string        `json:"name"`
+	DisplayTitle    string        `json:"display_title"`
+	WorkflowID      int64         `json:"workflow_id"`
+	WorkflowPath    string        `json:"workflow_path"`
+	Event           string        `json:"event"`
+	Status          string        `json:"status"`
+	Conclusion      string        `json:"conclusion,omitempty"`
+	HeadBranch      string        `json:"head_branch"`
+	HeadSHA         string        `json:"head_sha"`
+	RunNumber       int           `json:"run_number"`
+	RunAttempt      int           `json:"run_attempt"`
+	Actor           Actor         `json:"actor"`
+	TriggeringActor Actor         `json:"triggering_actor"`
+	PullRequests    []int         `json:"pull_requests,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+	RunStartedAt    *time.Time    `json:"run_started_at,omitempty"`
+	Jobs            []WorkflowJob `json:"jobs"`
+}
+
+type WorkflowJob struct {
+	ID          int64          `json:"id"`
+	URL         string         `json:"url"`
+	Name        string         `json:"name"`
+	RunAttempt  int            `json:"run_attempt"`
+	Status      string         `json:"status"`
+	Conclusion  string         `json:"conclusion,omitempty"`
+	RunnerName  string         `json:"runner_name,omitempty"`
+	Labels      []string       `json:"labels,omitempty"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	Steps       []WorkflowStep `json:"steps"`
+}
+
+type WorkflowStep struct {
+	Number      int        `json:"number"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type restUser struct {
+	Login     string `json:"login"`
+	Type      string `json:"type"`
+	NodeID    string `json:"node_id"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+}
+
+// workflowRunsSearchLimit is the most runs GitHub lists for a filtered
+// request, such as one with a created range.
+const workflowRunsSearchLimit = 1000
+
+type workflowRunsResponse struct {
+	TotalCount   int               `json:"total_count"`
+	WorkflowRuns []workflowRunNode `json:"workflow_runs"`
+}
+
+type workflowRunNode struct {
+	ID              int64      `json:"id"`
+	HTMLURL         string     `json:"html_url"`
+	Name            string     `json:"name"`
+	DisplayTitle    string     `json:"display_title"`
+	WorkflowID      int64      `json:"workflow_id"`
+	Path            string     `json:"path"`
+	Event           string     `json:"event"`
+	Status          string     `json:"status"`
+	Conclusion      *string    `json:"conclusion"`
+	HeadBranch      string     `json:"head_branch"`
+	HeadSHA         string     `json:"head_sha"`
+	RunNumber       int        `json:"run_number"`
+	RunAttempt      int        `json:"run_attempt"`
+	Actor           *restUser  `json:"actor"`
+	TriggeringActor *restUser  `json:"triggering_actor"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	RunStartedAt    *time.Time `json:"run_started_at"`
+	PullRequests    []struct {
+		Number int `json:"number"`
+	} `json:"pull_requests"`
+}
+
+type workflowJobsResponse struct {
+	Jobs []struct {
+		ID          int64      `json:"id"`
+		HTMLURL     string     `json:"html_url"`
+		Name        string     `json:"name"`
+		RunAttempt  int        `json:"run_attempt"`
+		Status      string     `json:"status"`
+		Conclusion  *string    `json:"conclusion"`
+		RunnerName  *string    `json:"runner_name"`
+		Labels      []string   `json:"labels"`
+		StartedAt   *time.Time `json:"started_at"`
+		CompletedAt *time.Time `json:"completed_at"`
+		Steps       []struct {
+			Number      int        `json:"number"`
+			Name        string     `json:"name"`
+			Status      string     `json:"status"`
+			Conclusion  *string    `json:"conclusion"`
+			StartedAt   *time.Time `json:"started_at"`
+			CompletedAt *time.Time `json:"completed_at"`
+		} `json:"steps"`
+	} `json:"jobs"`
+}
+
+// FetchWorkflowRuns passes the GitHub Actions runs created at or after
+// since to fn, oldest first, with the jobs and steps of every attempt. Runs
+// are handed over one at a time so that a long sync can save its progress.
+// The GraphQL schema has no Actions data, so this goes through the REST API.
+func (c *Client) FetchWorkflowRuns(ctx context.Context, owner, repo string, since *time.Time, fn func(WorkflowRun) error) error {
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs", url.PathEscape(owner), url.PathEscape(repo))
+
+	var nodes []workflowRunNode
+	var err error
+	if since == nil {
+		nodes, err = c.listWorkflowRuns(ctx, path, "")
+	} else {
+		nodes, err = c.listWorkflowRunsCreated(ctx, path, since.UTC().Truncate(time.Second), time.Now().UTC().Truncate(time.Second))
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].CreatedAt.Before(nodes[j].CreatedAt)
+	})
+	for _, n := range nodes {
+		run, err := c.convertWorkflowRun(ctx, path, n)
+		if err != nil {
+			return err
+		}
+		if err := fn(run); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FetchWorkflowRun returns a single run with its jobs, e.g. to update a run
+// that was still in progress when it was synced. It returns an error
+// wrapping ErrNotFound if the run has been deleted.
+func (c *Client) FetchWorkflowRun(ctx context.Context, owner, repo string, id int64) (WorkflowRun, error) {
+	path := fmt.Sprintf("/repos/%s/%s/actions/runs", url.PathEscape(owner), url.PathEscape(repo))
+
+	var n workflowRunNode
+	if err := c.restGet(ctx, fmt.Sprintf("%s/%d", path, id), nil, &n); err != nil {
+		return WorkflowRun{}, err
+	}
+	return c.convertWorkflowRun(ctx, path, n)
+}
+
+// errTooManyRuns reports a filtered listing that GitHub would truncate.
+var errTooManyRuns = errors.New("too many workflow runs")
+
+// listWorkflowRunsCreated lists the runs created between from and to, both
+// inclusive. GitHub lists at most workflowRunsSearchLimit runs for a created
+// range, so ranges with more runs are split in two until every part fits.
+func (c *Client) listWorkflowRunsCreated(ctx context.Context, path string, from, to time.Time) ([]workflowRunNode, error) {
+	nodes, err := c.listWorkflowRuns(ctx, path, from.Format(time.RFC3339)+".."+to.Format(time.RFC3339))
+	if !errors.Is(err, errTooManyRuns) {
+		return nodes, err
+	}
+	if to.Sub(from) < time.Second {
+		return nil, fmt.Errorf("more than %d workflow runs created at %s", workflowRunsSearchLimit, from.Format(time.RFC3339))
+	}
+
+	mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+	older, err := c.listWorkflowRunsCreated(ctx, path, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := c.listWorkflowRunsCreated(ctx, path, mid.Add(time.Second), to)
+	if err != nil {
+		return nil, err
+	}
+	return append(newer, older...), nil
+}
+
+// listWorkflowRuns lists every run matching the created filter, or every
+// run of the repository if it is empty. It returns errTooManyRuns, before
+// fetching more than the first page, if GitHub would truncate the listing.
+func (c *Client) listWorkflowRuns(ctx context.Context, path, created string) ([]workflowRunNode, error) {
+	var nodes []workflowRunNode
+	for page := 1; ; page++ {
+		query := url.Values{
+			"per_page": {strconv.Itoa(restPerPage)},
+			"page":     {strconv.Itoa(page)},
+		}
+		if created != "" {
+			query.Set("created", created)
+		}
+
+		var resp workflowRunsResponse
+		if err := c.restGet(ctx, path, query, &resp); err != nil {
+			return nil, err
+		}
+		if created != "" && resp.TotalCount > workflowRunsSearchLimit {
+			return nil, errTooManyRuns
+		}
+		nodes = append(nodes, resp.WorkflowRuns...)
+
+		if len(resp.WorkflowRuns) < restPerPage {
+			return nodes, nil
+		}
+	}
+}
+
+func (c *Client) convertWorkflowRun(ctx context.Context, runsPath string, r workflowRunNode) (WorkflowRun, error) {
+	run := WorkflowRun{
+		ID:              r.ID,
+		URL:             r.HTMLURL,
+		Name:            r.Name,
+		DisplayTitle:    r.DisplayTitle,
+		WorkflowID:      r.WorkflowID,
+		WorkflowPath:    r.Path,
+		Event:           r.Event,
+		Status:          r.Status,
+		HeadBranch:      r.HeadBranch,
+		HeadSHA:         r.HeadSHA,
+		RunNumber:       r.RunNumber,
+		RunAttempt:      r.RunAttempt,
+		Actor:           convertRESTUser(r.Actor),
+		TriggeringActor: convertRESTUser(r.TriggeringActor),
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+		RunStartedAt:    r.RunStartedAt,
+	}
+	if r.Conclusion != nil {
+		run.Conclusion = *r.Conclusion
+	}
+	for _, pr := range r.PullRequests {
+		run.PullRequests = append(run.PullRequests, pr.Number)
+	}
+
+	jobs, err := c.fetchWorkflowJobs(ctx, runsPath, r.ID)
+	if err != nil {
+		return WorkflowRun{}, fmt.Errorf("failed to fetch jobs of run %d: %w", r.ID, err)
+	}
+	run.Jobs = jobs
+	return run, nil
+}
+
+func (c *Client) fetchWorkflowJobs(ctx context.Context, runsPath string, runID int64) ([]WorkflowJob, error) {
+	var jobs []WorkflowJob
+	path := fmt.Sprintf("%s/%d/jobs", runsPath, runID)
+
+	for page := 1; ; page++ {
+		query := url.Values{
+			"filter":   {"all"},
+			"per_page": {strconv.Itoa(restPerPage)},
+			"page":     {strconv.Itoa(page)},
+		}
+
+		var resp workflowJobsResponse
+		if err := c.restGet(ctx, path, query, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, j := range resp.Jobs {
+			job := WorkflowJob{
+				ID:          j.ID,
+				URL:         j.HTMLURL,
+				Name:        j.Name,
+				RunAttempt:  j.RunAttempt,
+				Status:      j.Status,
+				Labels:      j.Labels,
+				StartedAt:   j.StartedAt,
+				CompletedAt: j.CompletedAt,
+			}
+			if j.Conclusion != nil {
+				job.Conclusion = *j.Conclusion
+			}
+			if j.RunnerName != nil {
+				job.RunnerName = *j.RunnerName
+			}
+			for _, s := range j.Steps {
+				step := WorkflowStep{
+					Number:      s.Number,
+					Name:        s.Name,
+					Status:      s.Status,
+					StartedAt:   s.StartedAt,
+					CompletedAt: s.CompletedAt,
+				}
+				if s.Conclusion != nil {
+					step.Conclusion = *s.Conclusion
+				}
+				job.Steps = append(job.Steps, step)
+			}
+			jobs = append(jobs, job)
+		}
+
+		if len(resp.Jobs) < restPerPage {
+			break
+		}
+	}
+
+	return jobs, nil
+}
+
+func convertRESTUser(u *restUser) Actor {
+	if u == nil {
+		return Actor{Login: ghostLogin, Ghost: true}
+	}
+	return Actor{
+		ID:        u.NodeID,
+		Login:     u.Login,
+		Type:      u.Type,
+		AvatarURL: u.AvatarURL,
+		URL:       u.HTMLURL,
+		Ghost:     u.Login == ghostLogin,
+	}
+}
@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runsServer serves the runs and jobs of a repository whose runs were
+// created at the given times, reporting more than workflowRunsSearchLimit
+// runs for any created range longer than an hour.
+func runsServer(t *testing.T, created []time.Time, ranges *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/jobs") {
+			w.Write([]byte(`{"jobs":[{"id":1,"name":"build","steps":[{"number":1,"name":"checkout"}]}]}`))
+			return
+		}
+		if r.URL.Path != "/repos/o/r/actions/runs" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var from, to time.Time
+		if c := r.URL.Query().Get("created"); c != "" {
+			*ranges = append(*ranges, c)
+			bounds := strings.Split(c, "..")
+			from, _ = time.Parse(time.RFC3339, bounds[0])
+			to, _ = time.Parse(time.RFC3339, bounds[1])
+			if to.Sub(from) > time.Hour {
+				w.Write([]byte(`{"total_count":1001,"workflow_runs":[]}`))
+				return
+			}
+		}
+
+		var resp workflowRunsResponse
+		for i, at := range created {
+			if !from.IsZero() && (at.Before(from) || at.After(to)) {
+				continue
+			}
+			resp.WorkflowRuns = append(resp.WorkflowRuns, workflowRunNode{ID: int64(i + 1), CreatedAt: at, Status: "completed"})
+		}
+		resp.TotalCount = len(resp.WorkflowRuns)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func TestFetchWorkflowRunsSplitsRanges(t *testing.T) {
+	since := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Second)
+	created := []time.Time{
+		since.Add(2 * time.Hour),
+		since,
+		since.Add(150 * time.Minute),
+		since.Add(time.Hour),
+	}
+	var ranges []string
+	c := newTestClient(t, runsServer(t, created, &ranges))
+
+	var ids []int64
+	err := c.FetchWorkflowRuns(context.Background(), "o", "r", &since, func(run WorkflowRun) error {
+		if len(run.Jobs) != 1 || len(run.Jobs[0].Steps) != 1 {
+			t.Errorf("run %d: unexpected jobs %+v", run.ID, run.Jobs)
+		}
+		ids = append(ids, run.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Runs are handed over oldest first, each exactly once.
+	if len(ids) != 4 || ids[0] != 2 || ids[1] != 4 || ids[2] != 1 || ids[3] != 3 {
+		t.Errorf("got runs %v, want [2 4 1 3]", ids)
+	}
+	if len(ranges) < 3 {
+		t.Errorf("got ranges %v, want the first range split", ranges)
+	}
+}
+
+func TestFetchWorkflowRunsStopsOnError(t *testing.T) {
+	since := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	var ranges []string
+	c := newTestClient(t, runsServer(t, []time.Time{since, since.Add(time.Minute)}, &ranges))
+
+	calls := 0
+	errStop := context.Canceled
+	err := c.FetchWorkflowRuns(context.Background(), "o", "r", &since, func(run WorkflowRun) error {
+		calls++
+		return errStop
+	})
+	if err != errStop || calls != 1 {
+		t.Errorf("got %v after %d calls, want %v after 1", err, calls, errStop)
+	}
+}
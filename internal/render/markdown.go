@@ -0,0 +1,317 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+)
+
+// entry is one dated block of an item's conversation.
+type entry struct {
+	at   time.Time
+	text string
+}
+
+// IssueMarkdown renders an issue as markdown with YAML front matter,
+// followed by its body and its comments and events in chronological order.
+func IssueMarkdown(issue *github.Issue) []byte {
+	var b bytes.Buffer
+	fm := frontMatter{}
+	fm.add("kind", "issue")
+	fm.add("number", issue.Number)
+	fm.add("title", issue.Title)
+	fm.add("state", issue.State)
+	fm.add("url", issue.URL)
+	fm.add("author", issue.Author.Login)
+	fm.add("labels", labelNames(issue.Labels))
+	fm.add("assignees", logins(issue.Assignees))
+	if issue.Milestone != nil {
+		fm.add("milestone", issue.Milestone.Title)
+	}
+	if issue.IssueType != nil {
+		fm.add("issue_type", issue.IssueType.Name)
+	}
+	fm.add("created_at", issue.CreatedAt)
+	fm.add("updated_at", issue.UpdatedAt)
+	if issue.ClosedAt != nil {
+		fm.add("closed_at", *issue.ClosedAt)
+	}
+	fm.write(&b)
+
+	writeHeader(&b, issue.Title, issue.Number, issue.Author, issue.CreatedAt, issue.Body)
+
+	var entries []entry
+	for _, c := range issue.Comments {
+		entries = append(entries, commentEntry(c))
+	}
+	entries = append(entries, eventEntries(issue.Events)...)
+	writeEntries(&b, entries)
+	return finish(&b)
+}
+
+// PullRequestMarkdown renders a pull request like IssueMarkdown, with its
+// reviews and their comments interleaved in the conversation.
+func PullRequestMarkdown(pr *github.PullRequest) []byte {
+	var b bytes.Buffer
+	fm := frontMatter{}
+	fm.add("kind", "pr")
+	fm.add("number", pr.Number)
+	fm.add("title", pr.Title)
+	fm.add("state", pr.State)
+	fm.add("url", pr.URL)
+	fm.add("author", pr.Author.Login)
+	fm.add("labels", labelNames(pr.Labels))
+	fm.add("assignees", logins(pr.Assignees))
+	if pr.Milestone != nil {
+		fm.add("milestone", pr.Milestone.Title)
+	}
+	fm.add("created_at", pr.CreatedAt)
+	fm.add("updated_at", pr.UpdatedAt)
+	if pr.ClosedAt != nil {
+		fm.add("closed_at", *pr.ClosedAt)
+	}
+	if pr.MergedAt != nil {
+		fm.add("merged_at", *pr.MergedAt)
+	}
+	fm.write(&b)
+
+	writeHeader(&b, pr.Title, pr.Number, pr.Author, pr.CreatedAt, pr.Body)
+
+	var entries []entry
+	for _, c := range pr.Comments {
+		entries = append(entries, commentEntry(c))
+	}
+	for _, r := range pr.Reviews {
+		entries = append(entries, reviewEntry(r))
+	}
+	for _, e := range eventEntries(pr.Events) {
+		// Commits synced before their date was recorded have none; they are
+		// placed at the start of the conversation.
+		if e.at.IsZero() {
+			e.at = pr.CreatedAt
+		}
+		entries = append(entries, e)
+	}
+	writeEntries(&b, entries)
+	return finish(&b)
+}
+
+// DiscussionMarkdown renders a discussion like IssueMarkdown. Replies
+// follow the comment they answer.
+func DiscussionMarkdown(disc *github.Discussion) []byte {
+	var b bytes.Buffer
+	fm := frontMatter{}
+	fm.add("kind", "discussion")
+	fm.add("number", disc.Number)
+	fm.add("title", disc.Title)
+	fm.add("category", disc.Category)
+	fm.add("closed", disc.Closed)
+	fm.add("answered", disc.IsAnswered)
+	fm.add("url", disc.URL)
+	fm.add("author", disc.Author.Login)
+	fm.add("labels", labelNames(disc.Labels))
+	fm.add("upvotes", disc.UpvoteCount)
+	fm.add("created_at", disc.CreatedAt)
+	fm.add("updated_at", disc.UpdatedAt)
+	if disc.ClosedAt != nil {
+		fm.add("closed_at", *disc.ClosedAt)
+	}
+	fm.write(&b)
+
+	writeHeader(&b, disc.Title, disc.Number, disc.Author, disc.CreatedAt, disc.Body)
+
+	var entries []entry
+	for _, c := range disc.Comments {
+		var t bytes.Buffer
+		title := "commented"
+		if c.IsAnswer {
+			title = "answered"
+		}
+		writeComment(&t, "###", c.Author, title, c.CreatedAt, c.Body)
+		for _, r := range c.Replies {
+			writeComment(&t, "####", r.Author, "replied", r.CreatedAt, r.Body)
+		}
+		entries = append(entries, entry{at: c.CreatedAt, text: t.String()})
+	}
+	entries = append(entries, eventEntries(disc.Events)...)
+	writeEntries(&b, entries)
+	return finish(&b)
+}
+
+// finish returns the document with a single trailing newline.
+func finish(b *bytes.Buffer) []byte {
+	return append(bytes.TrimRight(b.Bytes(), "\n"), '\n')
+}
+
+func writeHeader(b *bytes.Buffer, title string, number int, author github.Actor, createdAt time.Time, body string) {
+	fmt.Fprintf(b, "# %s (#%d)\n\n", title, number)
+	fmt.Fprintf(b, "_Opened by %s on %s_\n\n", mention(author), formatTime(createdAt))
+	if body = strings.TrimSpace(body); body != "" {
+		b.WriteString(body + "\n\n")
+	}
+}
+
+func writeEntries(b *bytes.Buffer, entries []entry) {
+	if len(entries) == 0 {
+		return
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].at.Before(entries[j].at) })
+
+	b.WriteString("## Timeline\n\n")
+	for _, e := range entries {
+		b.WriteString(e.text)
+	}
+}
+
+func writeComment(b *bytes.Buffer, heading string, author github.Actor, action string, at time.Time, body string) {
+	fmt.Fprintf(b, "%s %s %s on %s\n\n", heading, mention(author), action, formatTime(at))
+	if body = strings.TrimSpace(body); body != "" {
+		b.WriteString(body + "\n\n")
+	}
+}
+
+func commentEntry(c github.Comment) entry {
+	var b bytes.Buffer
+	action := "commented"
+	if c.IsMinimized {
+		action = "commented (hidden: " + strings.ToLower(c.MinimizedReason) + ")"
+	}
+	writeComment(&b, "###", c.Author, action, c.CreatedAt, c.Body)
+	return entry{at: c.CreatedAt, text: b.String()}
+}
+
+func reviewEntry(r github.Review) entry {
+	var b bytes.Buffer
+	action := "reviewed (" + strings.ToLower(strings.ReplaceAll(r.State, "_", " ")) + ")"
+	writeComment(&b, "###", r.Author, action, r.SubmittedAt, r.Body)
+	for _, c := range r.Comments {
+		fmt.Fprintf(&b, "#### %s on `%s`\n\n", mention(c.Author), c.Path)
+		if body := strings.TrimSpace(c.Body); body != "" {
+			b.WriteString(body + "\n\n")
+		}
+	}
+	return entry{at: r.SubmittedAt, text: b.String()}
+}
+
+// eventEntries renders events as one line each. Events without a date are
+// printed without one.
+func eventEntries(events []github.Event) []entry {
+	var entries []entry
+	for _, e := range events {
+		line := fmt.Sprintf("- %s %s", mention(e.Actor), EventText(e))
+		if !e.CreatedAt.IsZero() {
+			line += " on " + formatTime(e.CreatedAt)
+		}
+		entries = append(entries, entry{at: e.CreatedAt, text: line + "\n\n"})
+	}
+	return entries
+}
+
+// EventText describes an event without its actor and date, e.g.
+// "labeled (label: bug)".
+func EventText(e github.Event) string {
+	text := strings.NewReplacer("_", " ", "-", " ").Replace(e.Type)
+	if details := formatDetails(e.Details); details != "" {
+		text += " (" + details + ")"
+	}
+	return text
+}
+
+// formatDetails flattens event details to sorted key: value pairs. Details
+// read back from JSON are generic maps, so they are normalised through JSON
+// first.
+func formatDetails(details any) string {
+	if details == nil {
+		return ""
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return ""
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %v", k, m[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func mention(a github.Actor) string {
+	if a.Login == "" {
+		return "someone"
+	}
+	return "@" + a.Login
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// frontMatter collects YAML front matter fields in insertion order.
+type frontMatter struct {
+	keys   []string
+	values []any
+}
+
+func (fm *frontMatter) add(key string, value any) {
+	fm.keys = append(fm.keys, key)
+	fm.values = append(fm.values, value)
+}
+
+func (fm *frontMatter) write(b *bytes.Buffer) {
+	b.WriteString("---\n")
+	for i, k := range fm.keys {
+		fmt.Fprintf(b, "%s: %s\n", k, yamlValue(fm.values[i]))
+	}
+	b.WriteString("---\n\n")
+}
+
+// yamlValue formats a scalar or string list. Strings are quoted the Go way,
+// which yields valid YAML double-quoted scalars.
+func yamlValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case []string:
+		quoted := make([]string, len(v))
+		for i, s := range v {
+			quoted[i] = strconv.Quote(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
+
+func labelNames(labels []github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+func logins(actors []github.Actor) []string {
+	names := make([]string, 0, len(actors))
+	for _, a := range actors {
+		names = append(names, a.Login)
+	}
+	return names
+}
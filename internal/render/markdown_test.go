@@ -0,0 +1,170 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestEventEntries(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	entries := eventEntries([]github.Event{
+		{Type: "labeled", Actor: github.Actor{Login: "mona"}, CreatedAt: at, Details: map[string]string{"label": "bug"}},
+		{Type: "head_ref_force_pushed", Actor: github.Actor{Login: "mona"}},
+		{Type: "locked", CreatedAt: at, Details: map[string]string{"source": "observed"}},
+	})
+
+	want := []string{
+		"- @mona labeled (label: bug) on 2024-03-01 12:30 UTC\n\n",
+		"- @mona head ref force pushed\n\n",
+		"- someone locked (source: observed) on 2024-03-01 12:30 UTC\n\n",
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.text != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, e.text, want[i])
+		}
+	}
+}
+
+func TestFormatDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		details any
+		want    string
+	}{
+		{"nil", nil, ""},
+		{"sorted", map[string]string{"to": "b", "from": "a"}, "from: a, to: b"},
+		{"generic", map[string]any{"count": float64(2), "draft": true}, "count: 2, draft: true"},
+		{"struct", struct {
+			Label string `json:"label"`
+		}{"bug"}, "label: bug"},
+		{"not an object", []string{"a"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDetails(tt.details); got != tt.want {
+				t.Errorf("formatDetails = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLValue(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"say \"hi\"\n", `"say \"hi\"\n"`},
+		{at, "2024-03-01T11:30:00Z"},
+		{[]string{"bug", "good first issue"}, `["bug", "good first issue"]`},
+		{[]string{}, "[]"},
+		{42, "42"},
+		{true, "true"},
+	}
+	for _, tt := range tests {
+		if got := yamlValue(tt.value); got != tt.want {
+			t.Errorf("yamlValue(%#v) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIssueMarkdown(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	issue := &github.Issue{
+		Number:    7,
+		Title:     "Crash on start",
+		State:     "OPEN",
+		Author:    github.Actor{Login: "mona"},
+		Body:      "It crashes.",
+		Labels:    []github.Label{{Name: "bug"}},
+		CreatedAt: t0,
+		UpdatedAt: t0.Add(2 * time.Hour),
+		Comments: []github.Comment{
+			{Author: github.Actor{Login: "hubot"}, Body: "Second", CreatedAt: t0.Add(2 * time.Hour)},
+		},
+		Events: []github.Event{
+			{Type: "labeled", Actor: github.Actor{Login: "mona"}, CreatedAt: t0.Add(time.Hour), Details: map[string]string{"label": "bug"}},
+		},
+	}
+
+	got := string(IssueMarkdown(issue))
+	for _, s := range []string{
+		"---\nkind: \"issue\"\nnumber: 7\ntitle: \"Crash on start\"\n",
+		"labels: [\"bug\"]\n",
+		"# Crash on start (#7)\n\n_Opened by @mona on 2024-03-01 12:00 UTC_\n\nIt crashes.\n\n## Timeline\n\n",
+	} {
+		if !strings.Contains(got, s) {
+			t.Errorf("missing %q in:\n%s", s, got)
+		}
+	}
+	if strings.Contains(got, "closed_at") {
+		t.Errorf("open issue has closed_at:\n%s", got)
+	}
+	event := strings.Index(got, "- @mona labeled")
+	comment := strings.Index(got, "### @hubot commented")
+	if event < 0 || comment < 0 || event > comment {
+		t.Errorf("timeline is not in chronological order:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "Second\n") {
+		t.Errorf("document does not end with a single newline: %q", got[len(got)-10:])
+	}
+}
+
+func TestPullRequestMarkdownUndatedEvents(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	pr := &github.PullRequest{
+		Number:    8,
+		Title:     "Fix crash",
+		Author:    github.Actor{Login: "mona"},
+		CreatedAt: t0,
+		Comments: []github.Comment{
+			{Author: github.Actor{Login: "hubot"}, Body: "LGTM", CreatedAt: t0.Add(time.Hour)},
+		},
+		Events: []github.Event{
+			{Type: "committed", Actor: github.Actor{Login: "mona"}},
+		},
+	}
+
+	got := string(PullRequestMarkdown(pr))
+	event := strings.Index(got, "- @mona committed\n")
+	comment := strings.Index(got, "### @hubot commented")
+	if event < 0 || comment < 0 || event > comment {
+		t.Errorf("undated event is not placed before later comments:\n%s", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.New(dir)
+	if err := store.Save(storage.KindIssue, 7, &github.Issue{Number: 7, Title: "Crash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Render(dir, FormatMarkdown, []storage.Kind{storage.KindIssue}); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	path, err := storage.ItemPath(storage.KindIssue, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, strings.TrimSuffix(path, ".json")+".md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# Crash (#7)") {
+		t.Errorf("unexpected rendering:\n%s", data)
+	}
+
+	if err := Render(dir, "html", nil); err == nil {
+		t.Error("Render accepted an unknown format")
+	}
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+const FormatMarkdown = "markdown"
+
+// Render writes a rendering of every stored item of the given kinds next to
+// its JSON file in outputDir, e.g. issues/12/123.md.
+func Render(outputDir, format string, kinds []storage.Kind) error {
+	if format != FormatMarkdown {
+		return fmt.Errorf("unknown format: %s (valid: %s)", format, FormatMarkdown)
+	}
+
+	store := storage.New(outputDir)
+	for _, kind := range kinds {
+		keys, err := store.List(kind)
+		if err != nil {
+			return fmt.Errorf("failed to list %s items: %w", kind, err)
+		}
+		for _, key := range keys {
+			content, err := renderItem(store, kind, key)
+			if err != nil {
+				return err
+			}
+			if err := store.SaveSidecar(kind, key, ".md", content); err != nil {
+				return fmt.Errorf("failed to save %s %d: %w", kind, key, err)
+			}
+		}
+		fmt.Printf("Rendered %d %s items\n", len(keys), kind)
+	}
+	return nil
+}
+
+func renderItem(store storage.Backend, kind storage.Kind, key int) ([]byte, error) {
+	switch kind {
+	case storage.KindIssue:
+		var issue github.Issue
+		if err := store.Load(kind, key, &issue); err != nil {
+			return nil, fmt.Errorf("failed to load issue %d: %w", key, err)
+		}
+		return IssueMarkdown(&issue), nil
+	case storage.KindPR:
+		var pr github.PullRequest
+		if err := store.Load(kind, key, &pr); err != nil {
+			return nil, fmt.Errorf("failed to load PR %d: %w", key, err)
+		}
+		return PullRequestMarkdown(&pr), nil
+	case storage.KindDiscussion:
+		var disc github.Discussion
+		if err := store.Load(kind, key, &disc); err != nil {
+			return nil, fmt.Errorf("failed to load discussion %d: %w", key, err)
+		}
+		return DiscussionMarkdown(&disc), nil
+	}
+	return nil, fmt.Errorf("rendering %s items is not supported", kind)
+}
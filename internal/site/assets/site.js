@@ -0,0 +1,56 @@
+// Client-side filtering of list pages and search on the index page. The
+// site is static, so everything runs on data embedded in the pages.
+(function () {
+  var text = document.getElementById("filter-text");
+  var state = document.getElementById("filter-state");
+  var label = document.getElementById("filter-label");
+  var count = document.getElementById("filter-count");
+
+  if (state) {
+    var rows = Array.prototype.slice.call(document.querySelectorAll("table.items tbody tr"));
+    var filter = function () {
+      var q = text.value.toLowerCase();
+      var shown = 0;
+      rows.forEach(function (row) {
+        var labels = JSON.parse(row.dataset.labels || "[]");
+        var match = (!state.value || row.dataset.state === state.value) &&
+          (!label.value || labels.indexOf(label.value) >= 0) &&
+          (!q || row.textContent.toLowerCase().indexOf(q) >= 0);
+        row.style.display = match ? "" : "none";
+        if (match) shown++;
+      });
+      count.textContent = shown + " of " + rows.length;
+    };
+    [text, state, label].forEach(function (el) { el.addEventListener("input", filter); });
+    filter();
+  }
+
+  var search = document.getElementById("search");
+  var results = document.getElementById("results");
+  if (search && typeof searchIndex !== "undefined") {
+    var entries = searchIndex.map(function (e) {
+      return { entry: e, text: (e.t + "\n" + e.l.join(" ") + "\n" + e.x).toLowerCase() };
+    });
+    search.addEventListener("input", function () {
+      var terms = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+      results.textContent = "";
+      if (!terms.length) return;
+
+      var matches = entries.filter(function (e) {
+        return terms.every(function (t) { return e.text.indexOf(t) >= 0; });
+      }).slice(0, 100);
+      matches.forEach(function (m) {
+        var li = document.createElement("li");
+        var kind = document.createElement("span");
+        kind.className = "kind";
+        kind.textContent = m.entry.k + " · " + m.entry.s.toLowerCase() + " ";
+        var a = document.createElement("a");
+        a.href = m.entry.p;
+        a.textContent = "#" + m.entry.n + " " + m.entry.t;
+        li.appendChild(kind);
+        li.appendChild(a);
+        results.appendChild(li);
+      });
+    });
+  }
+})();
@@ -0,0 +1,100 @@
+package site
+
+import (
+	"bytes"
+	"html/template"
+	"regexp"
+	"strconv"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// newMarkdown returns a GitHub-flavoured markdown renderer that links #123
+// references through resolve. Raw HTML is left out of the output and
+// dangerous link targets are dropped, which is goldmark's default, so user
+// content cannot inject markup into the archive.
+func newMarkdown(resolve func(number int) string) goldmark.Markdown {
+	return goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(
+			parser.WithASTTransformers(util.Prioritized(&refLinker{resolve: resolve}, 999)),
+		),
+	)
+}
+
+func renderMarkdown(md goldmark.Markdown, source string) template.HTML {
+	var b bytes.Buffer
+	if err := md.Convert([]byte(source), &b); err != nil {
+		return template.HTML(template.HTMLEscapeString(source))
+	}
+	return template.HTML(b.String())
+}
+
+// refPattern matches #123 references that are not part of a word, an HTML
+// entity or a URL path.
+var refPattern = regexp.MustCompile(`(?:^|[^\w&/])(#(\d+))\b`)
+
+// refLinker turns #123 references in text into links to the local page of
+// the referenced item. References in code, links and raw HTML are kept.
+type refLinker struct {
+	resolve func(number int) string
+}
+
+func (l *refLinker) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	var texts []*ast.Text
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindLink, ast.KindAutoLink, ast.KindImage, ast.KindCodeSpan, ast.KindCodeBlock,
+			ast.KindFencedCodeBlock, ast.KindHTMLBlock, ast.KindRawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		if t, ok := n.(*ast.Text); ok {
+			texts = append(texts, t)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	source := reader.Source()
+	for _, t := range texts {
+		l.linkText(t, source)
+	}
+}
+
+// linkText splits a text node around the references it contains. The
+// original node keeps the text after the last reference, so its line break
+// flags stay in place.
+func (l *refLinker) linkText(t *ast.Text, source []byte) {
+	start := t.Segment.Start
+	value := t.Segment.Value(source)
+	parent := t.Parent()
+
+	pos := start
+	for _, m := range refPattern.FindAllSubmatchIndex(value, -1) {
+		number, err := strconv.Atoi(string(value[m[4]:m[5]]))
+		if err != nil {
+			continue
+		}
+		href := l.resolve(number)
+		if href == "" {
+			continue
+		}
+
+		if start+m[2] > pos {
+			parent.InsertBefore(parent, t, ast.NewTextSegment(text.NewSegment(pos, start+m[2])))
+		}
+		link := ast.NewLink()
+		link.Destination = []byte(href)
+		link.AppendChild(link, ast.NewTextSegment(text.NewSegment(start+m[2], start+m[3])))
+		parent.InsertBefore(parent, t, link)
+		pos = start + m[3]
+	}
+	t.Segment = t.Segment.WithStart(pos)
+}
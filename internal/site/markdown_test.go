@@ -0,0 +1,95 @@
+package site
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownRefs(t *testing.T) {
+	md := newMarkdown(func(number int) string {
+		if number == 404 {
+			return ""
+		}
+		return fmt.Sprintf("../issues/%d.html", number)
+	})
+
+	tests := []struct {
+		name   string
+		source string
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "plain reference",
+			source: "Fixed by #12.",
+			want:   []string{`Fixed by <a href="../issues/12.html">#12</a>.`},
+		},
+		{
+			name:   "several references",
+			source: "See #1 and #2",
+			want:   []string{`<a href="../issues/1.html">#1</a> and <a href="../issues/2.html">#2</a>`},
+		},
+		{
+			name:   "unknown item",
+			source: "See #404",
+			want:   []string{"See #404"},
+			absent: []string{"<a"},
+		},
+		{
+			name:   "code",
+			source: "`#12` and\n\n```\n#13\n```",
+			want:   []string{"<code>#12</code>", "#13\n"},
+			absent: []string{"<a"},
+		},
+		{
+			name:   "link text",
+			source: "[see #12](https://example.com)",
+			want:   []string{`<a href="https://example.com">see #12</a>`},
+			absent: []string{"issues/12.html"},
+		},
+		{
+			name:   "inside a word or URL",
+			source: "abc#12 and https://example.com/#12",
+			absent: []string{"issues/12.html"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(renderMarkdown(md, tt.source))
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("missing %q in %q", s, got)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(got, s) {
+					t.Errorf("unexpected %q in %q", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownUnsafeContent(t *testing.T) {
+	md := newMarkdown(func(int) string { return "" })
+
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{"script block", "<script>alert(1)</script>"},
+		{"inline html", "hello <img src=x onerror=alert(1)>"},
+		{"javascript link", "[click](javascript:alert(1))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(renderMarkdown(md, tt.source))
+			for _, s := range []string{"<script", "<img", "javascript:"} {
+				if strings.Contains(got, s) {
+					t.Errorf("rendered %q as %q", tt.source, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/render"
+)
+
+// page holds the fields shared by every page. Root is the relative path
+// from the page to the site root.
+type page struct {
+	Root  string
+	Repo  string
+	Title string
+}
+
+type indexPage struct {
+	page
+	Lists []*listPage
+}
+
+type listPage struct {
+	page
+	Kind   string
+	Rows   []listRow
+	States []string
+	Labels []string
+}
+
+type listRow struct {
+	Number    int
+	Title     string
+	Path      string
+	State     string
+	Labels    []github.Label
+	LabelData string
+	Author    string
+	CreatedAt time.Time
+	Comments  int
+}
+
+type itemPage struct {
+	page
+	Kind      string
+	KindName  string
+	Number    int
+	State     string
+	URL       string
+	Author    github.Actor
+	CreatedAt time.Time
+	Labels    []github.Label
+	Meta      []metaField
+	Body      template.HTML
+	Links     []linkGroup
+	Timeline  []timelineEntry
+
+	searchText string
+}
+
+type metaField struct {
+	Name  string
+	Value string
+}
+
+type linkGroup struct {
+	Name string
+	Refs []refLink
+}
+
+type refLink struct {
+	Text     string
+	Href     string
+	External bool
+}
+
+// timelineEntry is a comment, review or event in an item's conversation.
+// Discussion comments carry their replies.
+type timelineEntry struct {
+	At      time.Time
+	Type    string
+	Anchor  string
+	Author  github.Actor
+	Action  string
+	Body    template.HTML
+	Hidden  string
+	Notes   []reviewNote
+	Replies []timelineEntry
+
+	// Undated events are shown without a time.
+	Undated bool
+}
+
+type reviewNote struct {
+	Path   string
+	Author github.Actor
+	Body   template.HTML
+}
+
+type searchEntry struct {
+	Kind   string   `json:"k"`
+	Number int      `json:"n"`
+	Title  string   `json:"t"`
+	State  string   `json:"s"`
+	Labels []string `json:"l"`
+	Path   string   `json:"p"`
+	Text   string   `json:"x"`
+}
+
+func (g *generator) issuePage(issue *github.Issue) *itemPage {
+	p := &itemPage{
+		page:      page{Root: "../", Repo: g.repo, Title: issue.Title},
+		Kind:      "issues",
+		KindName:  "Issue",
+		Number:    issue.Number,
+		State:     issue.State,
+		URL:       issue.URL,
+		Author:    issue.Author,
+		CreatedAt: issue.CreatedAt,
+		Labels:    issue.Labels,
+		Body:      renderMarkdown(g.md, issue.Body),
+	}
+	p.addMeta("Assignees", joinLogins(issue.Assignees))
+	if issue.Milestone != nil {
+		p.addMeta("Milestone", issue.Milestone.Title)
+	}
+	if issue.IssueType != nil {
+		p.addMeta("Type", issue.IssueType.Name)
+	}
+	if issue.ClosedAt != nil {
+		p.addMeta("Closed", issue.ClosedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+
+	if issue.Parent != nil {
+		p.addLinks("Parent", g.itemRefs([]github.ItemRef{*issue.Parent}))
+	}
+	p.addLinks("Sub-issues", g.itemRefs(issue.SubIssues))
+	p.addLinks("Tracks", g.itemRefs(issue.TrackedIssues))
+	p.addLinks("Tracked in", g.itemRefs(issue.TrackedInIssues))
+	p.addLinks("Closed by", g.itemRefs(issue.ClosedByPullRequests))
+	p.addLinks("Referenced by", g.localRefs(g.refs[issue.Number]))
+
+	text := []string{issue.Body}
+	for _, c := range issue.Comments {
+		p.Timeline = append(p.Timeline, g.commentEntry(c))
+		text = append(text, c.Body)
+	}
+	p.Timeline = append(p.Timeline, eventEntries(issue.Events)...)
+	p.finish(text)
+	return p
+}
+
+func (g *generator) prPage(pr *github.PullRequest) *itemPage {
+	p := &itemPage{
+		page:      page{Root: "../", Repo: g.repo, Title: pr.Title},
+		Kind:      "pulls",
+		KindName:  "Pull request",
+		Number:    pr.Number,
+		State:     pr.State,
+		URL:       pr.URL,
+		Author:    pr.Author,
+		CreatedAt: pr.CreatedAt,
+		Labels:    pr.Labels,
+		Body:      renderMarkdown(g.md, pr.Body),
+	}
+	p.addMeta("Assignees", joinLogins(pr.Assignees))
+	if pr.Milestone != nil {
+		p.addMeta("Milestone", pr.Milestone.Title)
+	}
+	if pr.MergedAt != nil {
+		p.addMeta("Merged", pr.MergedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	} else if pr.ClosedAt != nil {
+		p.addMeta("Closed", pr.ClosedAt.UTC().Format("2006-01-02 15:04 UTC"))
+	}
+
+	p.addLinks("Closes", g.itemRefs(pr.ClosingIssues))
+	p.addLinks("Referenced by", g.localRefs(g.refs[pr.Number]))
+
+	text := []string{pr.Body}
+	for _, c := range pr.Comments {
+		p.Timeline = append(p.Timeline, g.commentEntry(c))
+		text = append(text, c.Body)
+	}
+	for _, r := range pr.Reviews {
+		entry := timelineEntry{
+			At:     r.SubmittedAt,
+			Type:   "review",
+			Anchor: r.ID,
+			Author: r.Author,
+			Action: "reviewed (" + strings.ToLower(strings.ReplaceAll(r.State, "_", " ")) + ")",
+			Body:   renderMarkdown(g.md, r.Body),
+		}
+		text = append(text, r.Body)
+		for _, c := range r.Comments {
+			entry.Notes = append(entry.Notes, reviewNote{Path: c.Path, Author: c.Author, Body: renderMarkdown(g.md, c.Body)})
+			text = append(text, c.Body)
+		}
+		p.Timeline = append(p.Timeline, entry)
+	}
+	for _, e := range eventEntries(pr.Events) {
+		// Commits synced before their date was recorded have none; they are
+		// placed at the start of the conversation.
+		if e.At.IsZero() {
+			e.At = pr.CreatedAt
+			e.Undated = true
+		}
+		p.Timeline = append(p.Timeline, e)
+	}
+	p.finish(text)
+	return p
+}
+
+func (g *generator) discussionPage(disc *github.Discussion) *itemPage {
+	state := "OPEN"
+	if disc.Closed {
+		state = "CLOSED"
+	}
+	p := &itemPage{
+		page:      page{Root: "../", Repo: g.repo, Title: disc.Title},
+		Kind:      "discussions",
+		KindName:  "Discussion",
+		Number:    disc.Number,
+		State:     state,
+		URL:       disc.URL,
+		Author:    disc.Author,
+		CreatedAt: disc.CreatedAt,
+		Labels:    disc.Labels,
+		Body:      renderMarkdown(g.md, disc.Body),
+	}
+	p.addMeta("Category", disc.Category)
+	if disc.IsAnswered {
+		p.addMeta("Answered", "yes")
+	}
+	p.addMeta("Upvotes", fmt.Sprint(disc.UpvoteCount))
+	p.addLinks("Referenced by", g.localRefs(g.refs[disc.Number]))
+
+	text := []string{disc.Body}
+	for _, c := range disc.Comments {
+		entry := timelineEntry{
+			At:     c.CreatedAt,
+			Type:   "comment",
+			Anchor: c.ID,
+			Author: c.Author,
+			Action: "commented",
+			Body:   renderMarkdown(g.md, c.Body),
+			Hidden: hiddenReason(c.IsMinimized, c.MinimizedReason),
+		}
+		if c.IsAnswer {
+			entry.Action = "answered"
+		}
+		text = append(text, c.Body)
+		for _, r := range c.Replies {
+			entry.Replies = append(entry.Replies, timelineEntry{
+				At:     r.CreatedAt,
+				Type:   "comment",
+				Anchor: r.ID,
+				Author: r.Author,
+				Action: "replied",
+				Body:   renderMarkdown(g.md, r.Body),
+				Hidden: hiddenReason(r.IsMinimized, r.MinimizedReason),
+			})
+			text = append(text, r.Body)
+		}
+		p.Timeline = append(p.Timeline, entry)
+	}
+	p.Timeline = append(p.Timeline, eventEntries(disc.Events)...)
+	p.finish(text)
+	return p
+}
+
+func (g *generator) commentEntry(c github.Comment) timelineEntry {
+	return timelineEntry{
+		At:     c.CreatedAt,
+		Type:   "comment",
+		Anchor: c.ID,
+		Author: c.Author,
+		Action: "commented",
+		Body:   renderMarkdown(g.md, c.Body),
+		Hidden: hiddenReason(c.IsMinimized, c.MinimizedReason),
+	}
+}
+
+func eventEntries(events []github.Event) []timelineEntry {
+	var entries []timelineEntry
+	for _, e := range events {
+		entries = append(entries, timelineEntry{
+			At:     e.CreatedAt,
+			Type:   "event",
+			Author: e.Actor,
+			Action: render.EventText(e),
+		})
+	}
+	return entries
+}
+
+func hiddenReason(minimized bool, reason string) string {
+	if !minimized {
+		return ""
+	}
+	if reason == "" {
+		return "hidden"
+	}
+	return "hidden: " + strings.ToLower(reason)
+}
+
+// itemRefs links references to local pages where the item is archived, and
+// to GitHub otherwise.
+func (g *generator) itemRefs(refs []github.ItemRef) []refLink {
+	var links []refLink
+	for _, ref := range refs {
+		if path, ok := g.pages[ref.Number]; ok && strings.EqualFold(ref.Repository, g.repo) {
+			links = append(links, refLink{Text: fmt.Sprintf("#%d %s", ref.Number, ref.Title), Href: "../" + path})
+			continue
+		}
+		links = append(links, refLink{Text: fmt.Sprintf("%s#%d %s", ref.Repository, ref.Number, ref.Title), Href: ref.URL, External: true})
+	}
+	return links
+}
+
+func (g *generator) localRefs(numbers []int) []refLink {
+	var links []refLink
+	for _, n := range numbers {
+		path, ok := g.pages[n]
+		if !ok {
+			continue
+		}
+		links = append(links, refLink{Text: fmt.Sprintf("#%d %s", n, g.titles[n]), Href: "../" + path})
+	}
+	return links
+}
+
+func (p *itemPage) addMeta(name, value string) {
+	if value != "" {
+		p.Meta = append(p.Meta, metaField{Name: name, Value: value})
+	}
+}
+
+func (p *itemPage) addLinks(name string, refs []refLink) {
+	if len(refs) > 0 {
+		p.Links = append(p.Links, linkGroup{Name: name, Refs: refs})
+	}
+}
+
+// finish orders the timeline and keeps the item's text for the search
+// index.
+func (p *itemPage) finish(text []string) {
+	sort.SliceStable(p.Timeline, func(i, j int) bool { return p.Timeline[i].At.Before(p.Timeline[j].At) })
+
+	s := strings.Join(text, "\n")
+	if len(s) > searchTextLimit {
+		s = strings.ToValidUTF8(s[:searchTextLimit], "")
+	}
+	p.searchText = s
+}
+
+func (p *itemPage) searchEntry() searchEntry {
+	return searchEntry{
+		Kind:   p.KindName,
+		Number: p.Number,
+		Title:  p.Title,
+		State:  p.State,
+		Labels: labelNames(p.Labels),
+		Path:   fmt.Sprintf("%s/%d.html", p.Kind, p.Number),
+		Text:   p.searchText,
+	}
+}
+
+func (l *listPage) add(p *itemPage, comments int) {
+	labels, _ := json.Marshal(labelNames(p.Labels))
+	l.Rows = append(l.Rows, listRow{
+		Number:    p.Number,
+		Title:     p.Title,
+		Path:      fmt.Sprintf("%d.html", p.Number),
+		State:     p.State,
+		Labels:    p.Labels,
+		LabelData: string(labels),
+		Author:    p.Author.Login,
+		CreatedAt: p.CreatedAt,
+		Comments:  comments,
+	})
+}
+
+// finish collects the states and labels offered as filters.
+func (l *listPage) finish() {
+	states, labels := map[string]bool{}, map[string]bool{}
+	for _, row := range l.Rows {
+		states[row.State] = true
+		for _, label := range row.Labels {
+			labels[label.Name] = true
+		}
+	}
+	l.States = sortedKeys(states)
+	l.Labels = sortedKeys(labels)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func labelNames(labels []github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+func joinLogins(actors []github.Actor) string {
+	var logins []string
+	for _, a := range actors {
+		logins = append(logins, a.Login)
+	}
+	return strings.Join(logins, ", ")
+}
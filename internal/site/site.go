@@ -0,0 +1,289 @@
+package site
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+	"github.com/yuin/goldmark"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed assets
+var assetFS embed.FS
+
+// searchTextLimit caps the text of each item kept in the search index, so
+// the index stays small enough to load in the browser.
+const searchTextLimit = 4000
+
+// Generate writes a static HTML archive of the issues, pull requests and
+// discussions in the JSON tree at inputDir to siteDir. The site has no
+// external dependencies and can be browsed from the file system.
+func Generate(inputDir, siteDir string) error {
+	g := &generator{
+		store:  storage.New(inputDir),
+		dir:    siteDir,
+		pages:  map[int]string{},
+		titles: map[int]string{},
+		refs:   map[int][]int{},
+	}
+	if err := g.load(); err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("").Funcs(template.FuncMap{
+		"date":     func(t time.Time) string { return t.UTC().Format("2006-01-02") },
+		"datetime": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 UTC") },
+		"lower":    strings.ToLower,
+		"labelStyle": func(l github.Label) template.CSS {
+			if !hexColor.MatchString(l.Color) {
+				return ""
+			}
+			return template.CSS("background-color: #" + l.Color)
+		},
+	}).ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return fmt.Errorf("failed to parse templates: %w", err)
+	}
+	g.tmpl = tmpl
+	g.md = newMarkdown(func(number int) string {
+		if path, ok := g.pages[number]; ok {
+			return "../" + path
+		}
+		return ""
+	})
+
+	if err := g.writeAssets(); err != nil {
+		return err
+	}
+	return g.writePages()
+}
+
+var hexColor = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+type generator struct {
+	store storage.Backend
+	dir   string
+	tmpl  *template.Template
+	md    goldmark.Markdown
+
+	repo        string
+	issues      []github.Issue
+	prs         []github.PullRequest
+	discussions []github.Discussion
+
+	// pages maps item numbers to their page, relative to the site root.
+	// Issues, pull requests and discussions share one number space.
+	pages  map[int]string
+	titles map[int]string
+	// refs maps item numbers to the numbers of the items referencing them.
+	refs map[int][]int
+}
+
+func (g *generator) load() error {
+	var repository github.Repository
+	err := g.store.Load(storage.KindRepository, 0, &repository)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to load repository: %w", err)
+	}
+	g.repo = repository.NameWithOwner
+
+	keys, err := g.store.List(storage.KindIssue)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		var issue github.Issue
+		if err := g.store.Load(storage.KindIssue, key, &issue); err != nil {
+			return fmt.Errorf("failed to load issue %d: %w", key, err)
+		}
+		g.issues = append(g.issues, issue)
+		g.pages[issue.Number] = fmt.Sprintf("issues/%d.html", issue.Number)
+		g.titles[issue.Number] = issue.Title
+		g.setRepo(issue.URL)
+	}
+
+	if keys, err = g.store.List(storage.KindPR); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		var pr github.PullRequest
+		if err := g.store.Load(storage.KindPR, key, &pr); err != nil {
+			return fmt.Errorf("failed to load PR %d: %w", key, err)
+		}
+		g.prs = append(g.prs, pr)
+		g.pages[pr.Number] = fmt.Sprintf("pulls/%d.html", pr.Number)
+		g.titles[pr.Number] = pr.Title
+		g.setRepo(pr.URL)
+	}
+
+	if keys, err = g.store.List(storage.KindDiscussion); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		var disc github.Discussion
+		if err := g.store.Load(storage.KindDiscussion, key, &disc); err != nil {
+			return fmt.Errorf("failed to load discussion %d: %w", key, err)
+		}
+		g.discussions = append(g.discussions, disc)
+		g.pages[disc.Number] = fmt.Sprintf("discussions/%d.html", disc.Number)
+		g.titles[disc.Number] = disc.Title
+		g.setRepo(disc.URL)
+	}
+
+	// Newest first, like GitHub's lists.
+	sort.Slice(g.issues, func(i, j int) bool { return g.issues[i].Number > g.issues[j].Number })
+	sort.Slice(g.prs, func(i, j int) bool { return g.prs[i].Number > g.prs[j].Number })
+	sort.Slice(g.discussions, func(i, j int) bool { return g.discussions[i].Number > g.discussions[j].Number })
+
+	for _, issue := range g.issues {
+		g.addRefs(issue.Number, issue.Events)
+	}
+	for _, pr := range g.prs {
+		g.addRefs(pr.Number, pr.Events)
+	}
+	return nil
+}
+
+// setRepo derives the repository name from an item URL when the repository
+// itself has not been synced.
+func (g *generator) setRepo(url string) {
+	if g.repo != "" {
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(url, "https://"), "/")
+	if len(parts) >= 3 {
+		g.repo = parts[1] + "/" + parts[2]
+	}
+}
+
+// addRefs records the same-repository sources of cross-reference events on
+// an item.
+func (g *generator) addRefs(number int, events []github.Event) {
+	for _, e := range events {
+		if e.Type != "cross-referenced" {
+			continue
+		}
+		details, ok := e.Details.(map[string]any)
+		if !ok {
+			continue
+		}
+		repo, _ := details["source_repository"].(string)
+		source, _ := details["source_number"].(float64)
+		if !strings.EqualFold(repo, g.repo) || source == 0 {
+			continue
+		}
+		if !containsInt(g.refs[number], int(source)) {
+			g.refs[number] = append(g.refs[number], int(source))
+		}
+	}
+}
+
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) writeAssets() error {
+	return fs.WalkDir(assetFS, "assets", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := assetFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return g.writeFile(path, data)
+	})
+}
+
+func (g *generator) writeFile(path string, data []byte) error {
+	path = filepath.Join(g.dir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (g *generator) writeTemplate(path, name string, data any) error {
+	var b strings.Builder
+	if err := g.tmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return g.writeFile(path, []byte(b.String()))
+}
+
+func (g *generator) writePages() error {
+	var search []searchEntry
+	lists := []*listPage{
+		{page: page{Root: "../", Repo: g.repo, Title: "Issues"}, Kind: "issues"},
+		{page: page{Root: "../", Repo: g.repo, Title: "Pull requests"}, Kind: "pulls"},
+		{page: page{Root: "../", Repo: g.repo, Title: "Discussions"}, Kind: "discussions"},
+	}
+
+	for _, issue := range g.issues {
+		p := g.issuePage(&issue)
+		if err := g.writeTemplate(g.pages[issue.Number], "item.html", p); err != nil {
+			return err
+		}
+		lists[0].add(p, len(issue.Comments))
+		search = append(search, p.searchEntry())
+	}
+	for _, pr := range g.prs {
+		p := g.prPage(&pr)
+		if err := g.writeTemplate(g.pages[pr.Number], "item.html", p); err != nil {
+			return err
+		}
+		lists[1].add(p, len(pr.Comments)+len(pr.Reviews))
+		search = append(search, p.searchEntry())
+	}
+	for _, disc := range g.discussions {
+		p := g.discussionPage(&disc)
+		if err := g.writeTemplate(g.pages[disc.Number], "item.html", p); err != nil {
+			return err
+		}
+		comments := 0
+		for _, c := range disc.Comments {
+			comments += 1 + len(c.Replies)
+		}
+		lists[2].add(p, comments)
+		search = append(search, p.searchEntry())
+	}
+
+	for _, l := range lists {
+		l.finish()
+		if err := g.writeTemplate(l.Kind+"/index.html", "list.html", l); err != nil {
+			return err
+		}
+	}
+
+	// The index is a script rather than JSON so the site also works when
+	// opened from the file system, where pages cannot fetch other files.
+	data, err := json.Marshal(search)
+	if err != nil {
+		return err
+	}
+	if err := g.writeFile("assets/search-index.js", []byte("var searchIndex = "+string(data)+";\n")); err != nil {
+		return err
+	}
+
+	index := indexPage{page: page{Repo: g.repo, Title: g.repo}, Lists: lists}
+	return g.writeTemplate("index.html", "index.html", index)
+}
@@ -0,0 +1,98 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestGenerate(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	input := t.TempDir()
+	store := storage.New(input)
+	items := []struct {
+		kind storage.Kind
+		key  int
+		item any
+	}{
+		{storage.KindIssue, 1, &github.Issue{
+			Number:    1,
+			Title:     "Crash <b>now</b>",
+			State:     "OPEN",
+			URL:       "https://github.com/octo/repo/issues/1",
+			Author:    github.Actor{Login: "mona"},
+			Body:      "Broken since #2.",
+			CreatedAt: t0,
+		}},
+		{storage.KindPR, 2, &github.PullRequest{
+			Number:    2,
+			Title:     "Fix crash",
+			State:     "MERGED",
+			Author:    github.Actor{Login: "mona"},
+			CreatedAt: t0,
+			Events: []github.Event{
+				{Type: "committed", Actor: github.Actor{Login: "mona"}},
+			},
+		}},
+		{storage.KindDiscussion, 3, &github.Discussion{
+			Number:    3,
+			Title:     "Ideas",
+			Author:    github.Actor{Login: "mona"},
+			CreatedAt: t0,
+			Events: []github.Event{
+				{Type: "locked", CreatedAt: t0.Add(time.Hour), Details: map[string]string{"source": "observed"}},
+			},
+		}},
+	}
+	for _, it := range items {
+		if err := store.Save(it.kind, it.key, it.item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	site := t.TempDir()
+	if err := Generate(input, site); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(site, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	issue := read("issues/1.html")
+	if !strings.Contains(issue, "Crash &lt;b&gt;now&lt;/b&gt;") {
+		t.Error("issue title is not escaped")
+	}
+	if !strings.Contains(issue, `<a href="../pulls/2.html">#2</a>`) {
+		t.Error("issue body does not link #2 to the local PR page")
+	}
+
+	pr := read("pulls/2.html")
+	if !strings.Contains(pr, "<strong>mona</strong> committed</div>") {
+		t.Errorf("undated event is shown with a time:\n%s", pr)
+	}
+
+	disc := read("discussions/3.html")
+	if !strings.Contains(disc, "<strong>someone</strong> locked (source: observed) <time>2024-03-01 13:00 UTC</time>") {
+		t.Errorf("observed event is not attributed to someone:\n%s", disc)
+	}
+
+	for _, path := range []string{"index.html", "issues/index.html", "pulls/index.html", "discussions/index.html", "assets/search-index.js"} {
+		if _, err := os.Stat(filepath.Join(site, path)); err != nil {
+			t.Errorf("missing %s: %v", path, err)
+		}
+	}
+	if index := read("assets/search-index.js"); !strings.HasPrefix(index, "var searchIndex = [") {
+		t.Errorf("unexpected search index: %.60s", index)
+	}
+}
@@ -0,0 +1,37 @@
+package storage
+
+// Kind identifies a kind of stored item.
+type Kind string
+
+const (
+	KindIssue         Kind = "issue"
+	KindPR            Kind = "pr"
+	KindDiscussion    Kind = "discussion"
+	KindRepository    Kind = "repo"
+	KindRelease       Kind = "release"
+	KindProject       Kind = "project"
+	KindWorkflowRun   Kind = "workflow-run"
+	KindCommitComment Kind = "commit-comment"
+)
+
+// kinds lists every kind.
+var kinds = []Kind{
+	KindIssue, KindPR, KindDiscussion, KindRepository,
+	KindRelease, KindProject, KindWorkflowRun, KindCommitComment,
+}
+
+// Backend is a sink for synced items. Items are addressed by kind and key:
+// the issue, PR, discussion or project number, or the database ID for kinds
+// without a number. The repository is a single item stored under key 0.
+//
+// Load and Delete return an error wrapping fs.ErrNotExist when the item has
+// not been stored.
+type Backend interface {
+	Save(kind Kind, key int, data any) error
+	Load(kind Kind, key int, v any) error
+	List(kind Kind) ([]int, error)
+	Delete(kind Kind, key int) error
+
+	LoadSyncState() (*SyncState, error)
+	SaveSyncState(state *SyncState) error
+}
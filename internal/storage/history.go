@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// historyTimeFormat names snapshot files. It sorts chronologically and is
+// safe in file names.
+const historyTimeFormat = "20060102T150405Z"
+
+// EnableHistory makes Save keep every version of an item. Each changed
+// version is also written to .history/<key>/<timestamp>.json next to the
+// item's file, e.g. issues/12/.history/123/20240115T103000Z.json.
+func (s *Storage) EnableHistory() {
+	s.history = true
+}
+
+// saveVersion records data as a new version of the item at path, unless it
+// matches the current version. The first time an item that predates the
+// history is changed, its current version is recorded too, dated with the
+// file's modification time.
+func (s *Storage) saveVersion(path string, data any) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err == nil && bytes.Equal(current, content) {
+		return nil
+	}
+
+	dir := historyDir(path)
+	if current != nil {
+		versions, err := listVersions(dir)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				return err
+			}
+			if err := writeFileAtomic(versionPath(dir, info.ModTime()), current); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeFileAtomic(versionPath(dir, time.Now()), content); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, content)
+}
+
+// Versions returns the times of the recorded versions of an item, oldest
+// first.
+func (s *Storage) Versions(kind Kind, key int) ([]time.Time, error) {
+	path, err := s.itemPath(kind, key)
+	if err != nil {
+		return nil, err
+	}
+	return listVersions(historyDir(path))
+}
+
+// LoadVersion reads the version of an item that was current at the given
+// time into v. It returns an error wrapping fs.ErrNotExist when no version
+// had been recorded by then.
+func (s *Storage) LoadVersion(kind Kind, key int, at time.Time, v any) error {
+	path, err := s.itemPath(kind, key)
+	if err != nil {
+		return err
+	}
+	dir := historyDir(path)
+	versions, err := listVersions(dir)
+	if err != nil {
+		return err
+	}
+
+	i := sort.Search(len(versions), func(i int) bool { return versions[i].After(at) })
+	if i == 0 {
+		return fmt.Errorf("%s %d has no version at %s: %w", kind, key, at.Format(time.RFC3339), fs.ErrNotExist)
+	}
+	return readJSON(versionPath(dir, versions[i-1]), v)
+}
+
+func historyDir(path string) string {
+	return filepath.Join(filepath.Dir(path), ".history", strings.TrimSuffix(filepath.Base(path), ".json"))
+}
+
+func versionPath(dir string, t time.Time) string {
+	return filepath.Join(dir, t.UTC().Format(historyTimeFormat)+".json")
+}
+
+func listVersions(dir string) ([]time.Time, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []time.Time
+	for _, e := range entries {
+		t, err := time.Parse(historyTimeFormat, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, t)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Before(versions[j]) })
+	return versions, nil
+}
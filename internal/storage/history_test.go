@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+)
+
+type versioned struct {
+	Title string `json:"title"`
+}
+
+func TestSaveVersions(t *testing.T) {
+	s := New(t.TempDir())
+	s.EnableHistory()
+
+	if err := s.Save(KindIssue, 1, versioned{"one"}); err != nil {
+		t.Fatal(err)
+	}
+	// Saving the same content again records no new version.
+	if err := s.Save(KindIssue, 1, versioned{"one"}); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := s.Versions(KindIssue, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("got %d versions, want 1", len(versions))
+	}
+
+	var got versioned
+	if err := s.LoadVersion(KindIssue, 1, time.Now().Add(time.Minute), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "one" {
+		t.Errorf("latest version has title %q, want one", got.Title)
+	}
+	if versions, err := s.Versions(KindIssue, 2); err != nil || len(versions) != 0 {
+		t.Errorf("Versions of an unsaved item = %v, %v", versions, err)
+	}
+}
+
+func TestSaveVersionsRecordsPreviousVersion(t *testing.T) {
+	s := New(t.TempDir())
+	if err := s.Save(KindIssue, 1, versioned{"old"}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := s.itemPath(KindIssue, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	synced := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, synced, synced); err != nil {
+		t.Fatal(err)
+	}
+
+	s.EnableHistory()
+	if err := s.Save(KindIssue, 1, versioned{"new"}); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := s.Versions(KindIssue, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || !versions[0].Equal(synced) {
+		t.Fatalf("versions = %v, want the previous version at %v first", versions, synced)
+	}
+
+	var got versioned
+	if err := s.LoadVersion(KindIssue, 1, synced, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "old" {
+		t.Errorf("version at %v has title %q, want old", synced, got.Title)
+	}
+}
+
+func TestLoadVersion(t *testing.T) {
+	s := New(t.TempDir())
+	path, err := s.itemPath(KindPR, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+	for _, v := range []struct {
+		at    time.Time
+		title string
+	}{{t2, "second"}, {t1, "first"}} {
+		if err := writeFileAtomic(versionPath(historyDir(path), v.at), []byte(`{"title":"`+v.title+`"}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{t1, "first"},
+		{t1.Add(time.Hour), "first"},
+		{t2, "second"},
+		{t2.Add(time.Hour), "second"},
+	}
+	for _, tt := range tests {
+		var got versioned
+		if err := s.LoadVersion(KindPR, 5, tt.at, &got); err != nil {
+			t.Fatalf("LoadVersion at %v: %v", tt.at, err)
+		}
+		if got.Title != tt.want {
+			t.Errorf("version at %v has title %q, want %q", tt.at, got.Title, tt.want)
+		}
+	}
+
+	var got versioned
+	err = s.LoadVersion(KindPR, 5, t1.Add(-time.Second), &got)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadVersion before the first version: got %v, want fs.ErrNotExist", err)
+	}
+}
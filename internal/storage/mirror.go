@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// Mirror returns a Backend that reads from primary and applies every write
+// to primary and then to each of mirrors, e.g. to keep a SQLite copy of the
+// JSON tree.
+func Mirror(primary Backend, mirrors ...Backend) Backend {
+	return &mirror{primary: primary, mirrors: mirrors}
+}
+
+type mirror struct {
+	primary Backend
+	mirrors []Backend
+}
+
+func (m *mirror) Save(kind Kind, key int, data any) error {
+	if err := m.primary.Save(kind, key, data); err != nil {
+		return err
+	}
+	for _, b := range m.mirrors {
+		if err := b.Save(kind, key, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mirror) Load(kind Kind, key int, v any) error {
+	return m.primary.Load(kind, key, v)
+}
+
+func (m *mirror) List(kind Kind) ([]int, error) {
+	return m.primary.List(kind)
+}
+
+// Delete removes the item from primary and from any mirror that has it. A
+// mirror may have been added after the item was stored.
+func (m *mirror) Delete(kind Kind, key int) error {
+	if err := m.primary.Delete(kind, key); err != nil {
+		return err
+	}
+	for _, b := range m.mirrors {
+		if err := b.Delete(kind, key); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mirror) LoadSyncState() (*SyncState, error) {
+	return m.primary.LoadSyncState()
+}
+
+func (m *mirror) SaveSyncState(state *SyncState) error {
+	if err := m.primary.SaveSyncState(state); err != nil {
+		return err
+	}
+	for _, b := range m.mirrors {
+		if err := b.SaveSyncState(state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,620 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	_ "modernc.org/sqlite"
+)
+
+// SQLite is a Backend that keeps the dump in a SQLite database. Issues, pull
+// requests and discussions are split into normalised tables for querying,
+// and every item is also kept whole as JSON so it can be loaded back. Kinds
+// without their own table are stored in the items table.
+type SQLite struct {
+	db *sql.DB
+}
+
+var _ Backend = (*SQLite)(nil)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS actors (
+	login TEXT PRIMARY KEY,
+	id TEXT,
+	type TEXT,
+	name TEXT,
+	avatar_url TEXT,
+	url TEXT,
+	ghost INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS labels (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	color TEXT,
+	description TEXT,
+	url TEXT
+);
+CREATE TABLE IF NOT EXISTS item_labels (
+	kind TEXT NOT NULL,
+	number INTEGER NOT NULL,
+	label_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	PRIMARY KEY (kind, number, label_id)
+);
+CREATE TABLE IF NOT EXISTS issues (
+	number INTEGER PRIMARY KEY,
+	id TEXT NOT NULL UNIQUE,
+	database_id INTEGER,
+	url TEXT,
+	title TEXT,
+	body TEXT,
+	state TEXT,
+	author_login TEXT,
+	author_association TEXT,
+	milestone TEXT,
+	issue_type TEXT,
+	created_at TEXT,
+	updated_at TEXT,
+	closed_at TEXT,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS pull_requests (
+	number INTEGER PRIMARY KEY,
+	id TEXT NOT NULL UNIQUE,
+	database_id INTEGER,
+	url TEXT,
+	title TEXT,
+	body TEXT,
+	state TEXT,
+	author_login TEXT,
+	author_association TEXT,
+	milestone TEXT,
+	created_at TEXT,
+	updated_at TEXT,
+	closed_at TEXT,
+	merged_at TEXT,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS discussions (
+	number INTEGER PRIMARY KEY,
+	id TEXT NOT NULL UNIQUE,
+	database_id INTEGER,
+	url TEXT,
+	title TEXT,
+	body TEXT,
+	author_login TEXT,
+	author_association TEXT,
+	category TEXT,
+	closed INTEGER NOT NULL,
+	state_reason TEXT,
+	locked INTEGER NOT NULL,
+	upvote_count INTEGER NOT NULL,
+	is_answered INTEGER NOT NULL,
+	answer_comment_id TEXT,
+	created_at TEXT,
+	updated_at TEXT,
+	closed_at TEXT,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS comments (
+	id TEXT PRIMARY KEY,
+	kind TEXT NOT NULL,
+	number INTEGER NOT NULL,
+	reply_to TEXT,
+	database_id INTEGER,
+	url TEXT,
+	author_login TEXT,
+	author_association TEXT,
+	body TEXT,
+	created_at TEXT,
+	updated_at TEXT,
+	is_minimized INTEGER NOT NULL,
+	minimized_reason TEXT,
+	is_answer INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS comments_item ON comments (kind, number);
+CREATE TABLE IF NOT EXISTS reviews (
+	id TEXT PRIMARY KEY,
+	number INTEGER NOT NULL,
+	database_id INTEGER,
+	url TEXT,
+	author_login TEXT,
+	author_association TEXT,
+	body TEXT,
+	state TEXT,
+	submitted_at TEXT
+);
+CREATE INDEX IF NOT EXISTS reviews_item ON reviews (number);
+CREATE TABLE IF NOT EXISTS review_comments (
+	id TEXT PRIMARY KEY,
+	review_id TEXT NOT NULL,
+	number INTEGER NOT NULL,
+	database_id INTEGER,
+	url TEXT,
+	author_login TEXT,
+	author_association TEXT,
+	path TEXT,
+	body TEXT,
+	created_at TEXT,
+	is_minimized INTEGER NOT NULL,
+	minimized_reason TEXT
+);
+CREATE INDEX IF NOT EXISTS review_comments_item ON review_comments (number);
+CREATE TABLE IF NOT EXISTS events (
+	kind TEXT NOT NULL,
+	number INTEGER NOT NULL,
+	seq INTEGER NOT NULL,
+	id TEXT,
+	type TEXT NOT NULL,
+	actor_login TEXT,
+	created_at TEXT,
+	details TEXT,
+	PRIMARY KEY (kind, number, seq)
+);
+CREATE TABLE IF NOT EXISTS items (
+	kind TEXT NOT NULL,
+	key INTEGER NOT NULL,
+	data TEXT NOT NULL,
+	PRIMARY KEY (kind, key)
+);
+CREATE TABLE IF NOT EXISTS sync_state (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	data TEXT NOT NULL
+);
+`
+
+// OpenSQLite opens, or creates, the SQLite database at path.
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// Save upserts an item. Child rows (comments, reviews, events, labels) of
+// the item are replaced, so rows removed on GitHub disappear as well.
+func (s *SQLite) Save(kind Kind, key int, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteItem(tx, kind, key); err != nil {
+		return err
+	}
+
+	switch v := data.(type) {
+	case github.Issue:
+		err = insertIssue(tx, &v, raw)
+	case *github.Issue:
+		err = insertIssue(tx, v, raw)
+	case github.PullRequest:
+		err = insertPR(tx, &v, raw)
+	case *github.PullRequest:
+		err = insertPR(tx, v, raw)
+	case github.Discussion:
+		err = insertDiscussion(tx, &v, raw)
+	case *github.Discussion:
+		err = insertDiscussion(tx, v, raw)
+	case github.Repository:
+		err = insertRepository(tx, &v, raw)
+	case *github.Repository:
+		err = insertRepository(tx, v, raw)
+	default:
+		_, err = tx.Exec(`INSERT INTO items (kind, key, data) VALUES (?, ?, ?)`, kind, key, string(raw))
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) Load(kind Kind, key int, v any) error {
+	query, args := itemQuery(kind, "data", &key)
+	var raw string
+	err := s.db.QueryRow(query, args...).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s %d: %w", kind, key, fs.ErrNotExist)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(raw), v)
+}
+
+func (s *SQLite) List(kind Kind) ([]int, error) {
+	query, args := itemQuery(kind, "", nil)
+	rows, err := s.db.Query(query+" ORDER BY 1", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []int
+	for rows.Next() {
+		var key int
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *SQLite) Delete(kind Kind, key int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query, args := itemQuery(kind, "", &key)
+	var found int
+	err = tx.QueryRow(query, args...).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s %d: %w", kind, key, fs.ErrNotExist)
+	}
+	if err != nil {
+		return err
+	}
+	if err := deleteItem(tx, kind, key); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Backfill copies every item of src, and then its sync state, into the
+// database. It returns the number of items copied. It is meant for a
+// database added to an output directory that was synced without it, which
+// would otherwise only receive the items changed after it was added.
+func (s *SQLite) Backfill(src Backend) (int, error) {
+	copied := 0
+	for _, kind := range kinds {
+		keys, err := src.List(kind)
+		if err != nil {
+			return copied, err
+		}
+		for _, key := range keys {
+			item := newItem(kind)
+			if err := src.Load(kind, key, item); err != nil {
+				return copied, fmt.Errorf("failed to load %s %d: %w", kind, key, err)
+			}
+			if err := s.Save(kind, key, item); err != nil {
+				return copied, fmt.Errorf("failed to save %s %d: %w", kind, key, err)
+			}
+			copied++
+		}
+	}
+
+	state, err := src.LoadSyncState()
+	if err != nil {
+		return copied, err
+	}
+	return copied, s.SaveSyncState(state)
+}
+
+// newItem returns a value to load an item of kind into, typed for the
+// kinds that Save splits into tables of their own.
+func newItem(kind Kind) any {
+	switch kind {
+	case KindIssue:
+		return &github.Issue{}
+	case KindPR:
+		return &github.PullRequest{}
+	case KindDiscussion:
+		return &github.Discussion{}
+	case KindRepository:
+		return &github.Repository{}
+	default:
+		return &json.RawMessage{}
+	}
+}
+
+func (s *SQLite) LoadSyncState() (*SyncState, error) {
+	var raw string
+	err := s.db.QueryRow(`SELECT data FROM sync_state WHERE id = 1`).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &SyncState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state SyncState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (s *SQLite) SaveSyncState(state *SyncState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO sync_state (id, data) VALUES (1, ?)`, string(raw))
+	return err
+}
+
+// sqliteTables holds the kinds that have a table of their own, keyed by
+// number. All other kinds share the items table.
+var sqliteTables = map[Kind]string{
+	KindIssue:      "issues",
+	KindPR:         "pull_requests",
+	KindDiscussion: "discussions",
+}
+
+// itemQuery builds a query selecting column from the stored items of a
+// kind, or from a single item when key is set. An empty column selects the
+// item key.
+func itemQuery(kind Kind, column string, key *int) (string, []any) {
+	var conds []string
+	var args []any
+
+	table, keyColumn := "items", "key"
+	if t, ok := sqliteTables[kind]; ok {
+		table, keyColumn = t, "number"
+	} else {
+		conds = append(conds, "kind = ?")
+		args = append(args, kind)
+	}
+	if key != nil {
+		conds = append(conds, keyColumn+" = ?")
+		args = append(args, *key)
+	}
+	if column == "" {
+		column = keyColumn
+	}
+
+	query := "SELECT " + column + " FROM " + table
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return query, args
+}
+
+func deleteItem(tx *sql.Tx, kind Kind, key int) error {
+	var stmts []string
+	if table, ok := sqliteTables[kind]; ok {
+		stmts = []string{
+			`DELETE FROM ` + table + ` WHERE number = ?2`,
+			`DELETE FROM item_labels WHERE kind = ?1 AND number = ?2`,
+			`DELETE FROM comments WHERE kind = ?1 AND number = ?2`,
+			`DELETE FROM events WHERE kind = ?1 AND number = ?2`,
+		}
+		if kind == KindPR {
+			stmts = append(stmts,
+				`DELETE FROM reviews WHERE number = ?2`,
+				`DELETE FROM review_comments WHERE number = ?2`,
+			)
+		}
+	} else {
+		stmts = []string{`DELETE FROM items WHERE kind = ?1 AND key = ?2`}
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt, kind, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertIssue(tx *sql.Tx, issue *github.Issue, raw []byte) error {
+	var milestone, issueType any
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Title
+	}
+	if issue.IssueType != nil {
+		issueType = issue.IssueType.Name
+	}
+
+	_, err := tx.Exec(`INSERT INTO issues (number, id, database_id, url, title, body, state, author_login,
+		author_association, milestone, issue_type, created_at, updated_at, closed_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		issue.Number, issue.ID, issue.DatabaseID, issue.URL, issue.Title, issue.Body, issue.State,
+		issue.Author.Login, issue.AuthorAssociation, milestone, issueType,
+		sqlTime(&issue.CreatedAt), sqlTime(&issue.UpdatedAt), sqlTime(issue.ClosedAt), string(raw))
+	if err != nil {
+		return fmt.Errorf("failed to insert issue %d: %w", issue.Number, err)
+	}
+
+	actors := append([]github.Actor{issue.Author}, issue.Assignees...)
+	return insertChildren(tx, KindIssue, issue.Number, actors, issue.Labels, issue.Comments, issue.Events)
+}
+
+func insertPR(tx *sql.Tx, pr *github.PullRequest, raw []byte) error {
+	var milestone any
+	if pr.Milestone != nil {
+		milestone = pr.Milestone.Title
+	}
+
+	_, err := tx.Exec(`INSERT INTO pull_requests (number, id, database_id, url, title, body, state, author_login,
+		author_association, milestone, created_at, updated_at, closed_at, merged_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		pr.Number, pr.ID, pr.DatabaseID, pr.URL, pr.Title, pr.Body, pr.State,
+		pr.Author.Login, pr.AuthorAssociation, milestone,
+		sqlTime(&pr.CreatedAt), sqlTime(&pr.UpdatedAt), sqlTime(pr.ClosedAt), sqlTime(pr.MergedAt), string(raw))
+	if err != nil {
+		return fmt.Errorf("failed to insert pull request %d: %w", pr.Number, err)
+	}
+
+	actors := append([]github.Actor{pr.Author}, pr.Assignees...)
+	for _, review := range pr.Reviews {
+		actors = append(actors, review.Author)
+		_, err := tx.Exec(`INSERT OR REPLACE INTO reviews (id, number, database_id, url, author_login,
+			author_association, body, state, submitted_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			review.ID, pr.Number, review.DatabaseID, review.URL, review.Author.Login,
+			review.AuthorAssociation, review.Body, review.State, sqlTime(&review.SubmittedAt))
+		if err != nil {
+			return fmt.Errorf("failed to insert review %s: %w", review.ID, err)
+		}
+
+		for _, c := range review.Comments {
+			actors = append(actors, c.Author)
+			_, err := tx.Exec(`INSERT OR REPLACE INTO review_comments (id, review_id, number, database_id, url,
+				author_login, author_association, path, body, created_at, is_minimized, minimized_reason)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				c.ID, review.ID, pr.Number, c.DatabaseID, c.URL, c.Author.Login, c.AuthorAssociation,
+				c.Path, c.Body, sqlTime(&c.CreatedAt), c.IsMinimized, c.MinimizedReason)
+			if err != nil {
+				return fmt.Errorf("failed to insert review comment %s: %w", c.ID, err)
+			}
+		}
+	}
+
+	return insertChildren(tx, KindPR, pr.Number, actors, pr.Labels, pr.Comments, pr.Events)
+}
+
+func insertDiscussion(tx *sql.Tx, disc *github.Discussion, raw []byte) error {
+	var answer any
+	if disc.Answer != nil {
+		answer = disc.Answer.CommentID
+	}
+
+	_, err := tx.Exec(`INSERT INTO discussions (number, id, database_id, url, title, body, author_login,
+		author_association, category, closed, state_reason, locked, upvote_count, is_answered,
+		answer_comment_id, created_at, updated_at, closed_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		disc.Number, disc.ID, disc.DatabaseID, disc.URL, disc.Title, disc.Body, disc.Author.Login,
+		disc.AuthorAssociation, disc.Category, disc.Closed, disc.StateReason, disc.Locked,
+		disc.UpvoteCount, disc.IsAnswered, answer,
+		sqlTime(&disc.CreatedAt), sqlTime(&disc.UpdatedAt), sqlTime(disc.ClosedAt), string(raw))
+	if err != nil {
+		return fmt.Errorf("failed to insert discussion %d: %w", disc.Number, err)
+	}
+
+	// Comments and their replies share the comments table, with replies
+	// pointing at the comment they answer.
+	actors := []github.Actor{disc.Author}
+	for _, c := range disc.Comments {
+		actors = append(actors, c.Author)
+		if err := insertComment(tx, KindDiscussion, disc.Number, nil, c.ID, c.DatabaseID, c.URL, c.Author,
+			c.AuthorAssociation, c.Body, c.CreatedAt, c.UpdatedAt, c.IsMinimized, c.MinimizedReason, c.IsAnswer); err != nil {
+			return err
+		}
+		for _, r := range c.Replies {
+			actors = append(actors, r.Author)
+			if err := insertComment(tx, KindDiscussion, disc.Number, c.ID, r.ID, r.DatabaseID, r.URL, r.Author,
+				r.AuthorAssociation, r.Body, r.CreatedAt, r.UpdatedAt, r.IsMinimized, r.MinimizedReason, r.IsAnswer); err != nil {
+				return err
+			}
+		}
+	}
+
+	return insertChildren(tx, KindDiscussion, disc.Number, actors, disc.Labels, nil, disc.Events)
+}
+
+// insertRepository stores the repository in the items table and refreshes
+// the label catalog from it.
+func insertRepository(tx *sql.Tx, repository *github.Repository, raw []byte) error {
+	if _, err := tx.Exec(`INSERT INTO items (kind, key, data) VALUES (?, 0, ?)`, KindRepository, string(raw)); err != nil {
+		return err
+	}
+	for _, l := range repository.Labels {
+		if err := upsertLabel(tx, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertChildren(tx *sql.Tx, kind Kind, number int, actors []github.Actor, labels []github.Label, comments []github.Comment, events []github.Event) error {
+	for _, l := range labels {
+		if err := upsertLabel(tx, l); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO item_labels (kind, number, label_id, name) VALUES (?, ?, ?, ?)`,
+			kind, number, l.ID, l.Name); err != nil {
+			return fmt.Errorf("failed to insert label %s: %w", l.Name, err)
+		}
+	}
+
+	for _, c := range comments {
+		actors = append(actors, c.Author)
+		if err := insertComment(tx, kind, number, nil, c.ID, c.DatabaseID, c.URL, c.Author,
+			c.AuthorAssociation, c.Body, c.CreatedAt, c.UpdatedAt, c.IsMinimized, c.MinimizedReason, false); err != nil {
+			return err
+		}
+	}
+
+	for i, e := range events {
+		actors = append(actors, e.Actor)
+		var details any
+		if e.Details != nil {
+			data, err := json.Marshal(e.Details)
+			if err != nil {
+				return err
+			}
+			details = string(data)
+		}
+		if _, err := tx.Exec(`INSERT INTO events (kind, number, seq, id, type, actor_login, created_at, details)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			kind, number, i, e.ID, e.Type, e.Actor.Login, sqlTime(&e.CreatedAt), details); err != nil {
+			return fmt.Errorf("failed to insert event %s: %w", e.Type, err)
+		}
+	}
+
+	for _, a := range actors {
+		if a.Login == "" {
+			continue
+		}
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO actors (login, id, type, name, avatar_url, url, ghost)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			a.Login, a.ID, a.Type, a.Name, a.AvatarURL, a.URL, a.Ghost); err != nil {
+			return fmt.Errorf("failed to insert actor %s: %w", a.Login, err)
+		}
+	}
+	return nil
+}
+
+func insertComment(tx *sql.Tx, kind Kind, number int, replyTo any, id string, databaseID int64, url string, author github.Actor,
+	association, body string, createdAt, updatedAt time.Time, minimized bool, minimizedReason string, isAnswer bool) error {
+	_, err := tx.Exec(`INSERT OR REPLACE INTO comments (id, kind, number, reply_to, database_id, url, author_login,
+		author_association, body, created_at, updated_at, is_minimized, minimized_reason, is_answer)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, kind, number, replyTo, databaseID, url, author.Login, association, body,
+		sqlTime(&createdAt), sqlTime(&updatedAt), minimized, minimizedReason, isAnswer)
+	if err != nil {
+		return fmt.Errorf("failed to insert comment %s: %w", id, err)
+	}
+	return nil
+}
+
+func upsertLabel(tx *sql.Tx, l github.Label) error {
+	_, err := tx.Exec(`INSERT OR REPLACE INTO labels (id, name, color, description, url) VALUES (?, ?, ?, ?, ?)`,
+		l.ID, l.Name, l.Color, l.Description, l.URL)
+	if err != nil {
+		return fmt.Errorf("failed to insert label %s: %w", l.Name, err)
+	}
+	return nil
+}
+
+// sqlTime formats a timestamp as RFC3339 text, or NULL when unset.
+func sqlTime(t *time.Time) any {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+)
+
+func openTestSQLite(t *testing.T) *SQLite {
+	t.Helper()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "dump.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func countRows(t *testing.T, db *SQLite, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	db := openTestSQLite(t)
+	issue := github.Issue{
+		ID:     "I_1",
+		Number: 12,
+		Title:  "first",
+		Author: github.Actor{Login: "mona"},
+		Labels: []github.Label{{ID: "L_1", Name: "bug"}},
+		Comments: []github.Comment{
+			{ID: "IC_1", Author: github.Actor{Login: "octocat"}, Body: "one"},
+			{ID: "IC_2", Author: github.Actor{Login: "mona"}, Body: "two"},
+		},
+		Events: []github.Event{{Type: "labeled", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	if err := db.Save(KindIssue, issue.Number, issue); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM comments WHERE kind = ? AND number = 12`, KindIssue); n != 2 {
+		t.Errorf("got %d comments, want 2", n)
+	}
+
+	// Saving again replaces the child rows.
+	issue.Title = "second"
+	issue.Comments = issue.Comments[:1]
+	if err := db.Save(KindIssue, issue.Number, &issue); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM comments WHERE kind = ? AND number = 12`, KindIssue); n != 1 {
+		t.Errorf("got %d comments after update, want 1", n)
+	}
+
+	var loaded github.Issue
+	if err := db.Load(KindIssue, 12, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Title != "second" || len(loaded.Comments) != 1 {
+		t.Errorf("unexpected issue: %+v", loaded)
+	}
+
+	release := github.Release{ID: "RE_1", DatabaseID: 77, TagName: "v1"}
+	if err := db.Save(KindRelease, 77, release); err != nil {
+		t.Fatal(err)
+	}
+	for kind, want := range map[Kind][]int{KindIssue: {12}, KindRelease: {77}, KindPR: nil} {
+		if got, err := db.List(kind); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("List(%s) = %v, %v, want %v", kind, got, err, want)
+		}
+	}
+
+	if err := db.Delete(KindIssue, 12); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM comments`); n != 0 {
+		t.Errorf("got %d comments after delete, want 0", n)
+	}
+	if err := db.Load(KindIssue, 12, &loaded); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Load after Delete: got %v, want fs.ErrNotExist", err)
+	}
+	if err := db.Delete(KindIssue, 12); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("second Delete: got %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestSQLiteBackfill(t *testing.T) {
+	src := New(t.TempDir())
+	synced := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	items := map[Kind]any{
+		KindIssue:      github.Issue{Number: 3, Title: "issue", Comments: []github.Comment{{ID: "IC_1"}}},
+		KindPR:         github.PullRequest{Number: 4, Title: "pr"},
+		KindDiscussion: github.Discussion{Number: 5, Title: "discussion"},
+		KindRepository: github.Repository{NameWithOwner: "o/r"},
+		KindRelease:    github.Release{DatabaseID: 6, TagName: "v1"},
+	}
+	for kind, item := range items {
+		key := 0
+		switch v := item.(type) {
+		case github.Issue:
+			key = v.Number
+		case github.PullRequest:
+			key = v.Number
+		case github.Discussion:
+			key = v.Number
+		case github.Release:
+			key = int(v.DatabaseID)
+		}
+		if err := src.Save(kind, key, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := src.SaveSyncState(&SyncState{Issues: &synced}); err != nil {
+		t.Fatal(err)
+	}
+
+	db := openTestSQLite(t)
+	copied, err := db.Backfill(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != len(items) {
+		t.Errorf("copied %d items, want %d", copied, len(items))
+	}
+
+	if n := countRows(t, db, `SELECT COUNT(*) FROM comments WHERE id = 'IC_1'`); n != 1 {
+		t.Errorf("issue comments were not split into the comments table")
+	}
+	var pr github.PullRequest
+	if err := db.Load(KindPR, 4, &pr); err != nil || pr.Title != "pr" {
+		t.Errorf("Load(pr, 4) = %+v, %v", pr, err)
+	}
+	var release github.Release
+	if err := db.Load(KindRelease, 6, &release); err != nil || release.TagName != "v1" {
+		t.Errorf("Load(release, 6) = %+v, %v", release, err)
+	}
+	var repo github.Repository
+	if err := db.Load(KindRepository, 0, &repo); err != nil || repo.NameWithOwner != "o/r" {
+		t.Errorf("Load(repo, 0) = %+v, %v", repo, err)
+	}
+
+	state, err := db.LoadSyncState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Issues == nil || !state.Issues.Equal(synced) {
+		t.Errorf("sync state = %+v, want the source's", state)
+	}
+}
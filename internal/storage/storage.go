@@ -5,17 +5,41 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type SyncState struct {
-	Issues      *time.Time `json:"issues,omitempty"`
-	PRs         *time.Time `json:"prs,omitempty"`
-	Discussions *time.Time `json:"discussions,omitempty"`
+	Issues         *time.Time `json:"issues,omitempty"`
+	PRs            *time.Time `json:"prs,omitempty"`
+	Discussions    *time.Time `json:"discussions,omitempty"`
+	Repository     *time.Time `json:"repository,omitempty"`
+	Releases       *time.Time `json:"releases,omitempty"`
+	Projects       *time.Time `json:"projects,omitempty"`
+	WorkflowRuns   *time.Time `json:"workflow_runs,omitempty"`
+	CommitComments *time.Time `json:"commit_comments,omitempty"`
 }
 
+// Storage is the Backend that keeps one JSON file per item in a directory
+// tree under baseDir.
 type Storage struct {
 	baseDir string
+	history bool
+}
+
+var _ Backend = (*Storage)(nil)
+
+// kindDirs maps each kind to its directory under the base directory.
+var kindDirs = map[Kind]string{
+	KindIssue:         "issues",
+	KindPR:            "pull_requests",
+	KindDiscussion:    "discussions",
+	KindRelease:       "releases",
+	KindProject:       "projects",
+	KindWorkflowRun:   "workflow_runs",
+	KindCommitComment: "commit_comments",
 }
 
 func New(baseDir string) *Storage {
@@ -23,13 +47,8 @@ func New(baseDir string) *Storage {
 }
 
 func (s *Storage) EnsureDirs() error {
-	dirs := []string{
-		filepath.Join(s.baseDir, "issues"),
-		filepath.Join(s.baseDir, "pull_requests"),
-		filepath.Join(s.baseDir, "discussions"),
-	}
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
+	for _, dir := range kindDirs {
+		if err := os.MkdirAll(filepath.Join(s.baseDir, dir), 0755); err != nil {
 			return err
 		}
 	}
@@ -58,19 +77,132 @@ func (s *Storage) SaveSyncState(state *SyncState) error {
 	return s.atomicWrite(path, state)
 }
 
-func (s *Storage) SaveIssue(number int, data any) error {
-	path := filepath.Join(s.baseDir, "issues", numberPrefix(number), formatNumber(number)+".json")
+// Save writes an item to its JSON file, replacing any previous version.
+func (s *Storage) Save(kind Kind, key int, data any) error {
+	path, err := s.itemPath(kind, key)
+	if err != nil {
+		return err
+	}
+	if s.history {
+		return s.saveVersion(path, data)
+	}
 	return s.atomicWrite(path, data)
 }
 
-func (s *Storage) SavePR(number int, data any) error {
-	path := filepath.Join(s.baseDir, "pull_requests", numberPrefix(number), formatNumber(number)+".json")
-	return s.atomicWrite(path, data)
+// Load reads a previously saved item into v.
+func (s *Storage) Load(kind Kind, key int, v any) error {
+	path, err := s.itemPath(kind, key)
+	if err != nil {
+		return err
+	}
+	return readJSON(path, v)
 }
 
-func (s *Storage) SaveDiscussion(number int, data any) error {
-	path := filepath.Join(s.baseDir, "discussions", numberPrefix(number), formatNumber(number)+".json")
-	return s.atomicWrite(path, data)
+// List returns the keys of all stored items of a kind in ascending order.
+func (s *Storage) List(kind Kind) ([]int, error) {
+	if kind == KindRepository {
+		_, err := os.Stat(filepath.Join(s.baseDir, "repository.json"))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []int{0}, nil
+	}
+
+	dir, ok := kindDirs[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind: %s", kind)
+	}
+	matches, err := filepath.Glob(filepath.Join(s.baseDir, dir, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []int
+	for _, match := range matches {
+		key, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(match), ".json"))
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys, nil
+}
+
+// Delete removes a stored item.
+func (s *Storage) Delete(kind Kind, key int) error {
+	path, err := s.itemPath(kind, key)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// SaveSidecar writes content next to an item's JSON file, in a file with
+// the same name and the given extension, e.g. 123.md.
+func (s *Storage) SaveSidecar(kind Kind, key int, ext string, content []byte) error {
+	path, err := s.itemPath(kind, key)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(strings.TrimSuffix(path, ".json")+ext, content)
+}
+
+// ItemAt returns the kind and key of the item stored at path, given relative
+// to the base directory. ok is false for files that do not hold an item,
+// such as the sync state or sidecar files.
+func ItemAt(path string) (kind Kind, key int, ok bool) {
+	path = filepath.ToSlash(path)
+	if path == "repository.json" {
+		return KindRepository, 0, true
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || !strings.HasSuffix(parts[2], ".json") {
+		return "", 0, false
+	}
+	key, err := strconv.Atoi(strings.TrimSuffix(parts[2], ".json"))
+	if err != nil {
+		return "", 0, false
+	}
+	for k, dir := range kindDirs {
+		if dir == parts[0] {
+			return k, key, true
+		}
+	}
+	return "", 0, false
+}
+
+// ItemPath returns the path of an item's file relative to the base
+// directory. It is the inverse of ItemAt.
+func ItemPath(kind Kind, key int) (string, error) {
+	if kind == KindRepository {
+		return "repository.json", nil
+	}
+	dir, ok := kindDirs[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown kind: %s", kind)
+	}
+	return filepath.Join(dir, numberPrefix(key), formatNumber(key)+".json"), nil
+}
+
+func (s *Storage) itemPath(kind Kind, key int) (string, error) {
+	path, err := ItemPath(kind, key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.baseDir, path), nil
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
 }
 
 func (s *Storage) atomicWrite(path string, data any) error {
@@ -78,7 +210,10 @@ func (s *Storage) atomicWrite(path string, data any) error {
 	if err != nil {
 		return err
 	}
+	return writeFileAtomic(path, jsonData)
+}
 
+func writeFileAtomic(path string, content []byte) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
@@ -89,7 +224,7 @@ func (s *Storage) atomicWrite(path string, data any) error {
 	}
 	tmpName := tmp.Name()
 
-	if _, err := tmp.Write(jsonData); err != nil {
+	if _, err := tmp.Write(content); err != nil {
 		tmp.Close()
 		os.Remove(tmpName)
 		return err
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestItemPathRoundTrip(t *testing.T) {
+	for _, kind := range kinds {
+		keys := []int{0, 7, 42, 123, 98765, 4294967296}
+		if kind == KindRepository {
+			keys = []int{0}
+		}
+		for _, key := range keys {
+			path, err := ItemPath(kind, key)
+			if err != nil {
+				t.Fatalf("ItemPath(%s, %d): %v", kind, key, err)
+			}
+			gotKind, gotKey, ok := ItemAt(path)
+			if !ok || gotKind != kind || gotKey != key {
+				t.Errorf("ItemAt(%q) = %s, %d, %v, want %s, %d, true", path, gotKind, gotKey, ok, kind, key)
+			}
+		}
+	}
+
+	if path, _ := ItemPath(KindIssue, 7); path != filepath.Join("issues", "7", "7.json") {
+		t.Errorf("single-digit key stored at %s", path)
+	}
+	if path, _ := ItemPath(KindIssue, 123); path != filepath.Join("issues", "12", "123.json") {
+		t.Errorf("key 123 stored at %s", path)
+	}
+	if _, err := ItemPath("unknown", 1); err == nil {
+		t.Error("ItemPath accepted an unknown kind")
+	}
+}
+
+func TestItemAtIgnoresOtherFiles(t *testing.T) {
+	for _, path := range []string{
+		".sync-state.json",
+		"issues/12/123.md",
+		"issues/12/notes.json",
+		"changes/20240101T000000Z.jsonl",
+		"unknown/12/123.json",
+		"issues/12/.history/123/20240101T000000Z.json",
+	} {
+		if kind, key, ok := ItemAt(path); ok {
+			t.Errorf("ItemAt(%q) = %s, %d, want not an item", path, kind, key)
+		}
+	}
+}
+
+func TestStorageCRUD(t *testing.T) {
+	s := New(t.TempDir())
+	type item struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+	}
+
+	for _, kind := range kinds {
+		var keys []int
+		if kind == KindRepository {
+			keys = []int{0}
+		} else {
+			keys = []int{123, 5, 12}
+		}
+		for _, key := range keys {
+			if err := s.Save(kind, key, item{Number: key, Title: "first"}); err != nil {
+				t.Fatalf("Save(%s, %d): %v", kind, key, err)
+			}
+		}
+		if err := s.Save(kind, keys[0], item{Number: keys[0], Title: "second"}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.List(kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []int{0}
+		if kind != KindRepository {
+			want = []int{5, 12, 123}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("List(%s) = %v, want %v", kind, got, want)
+		}
+
+		var loaded item
+		if err := s.Load(kind, keys[0], &loaded); err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Title != "second" {
+			t.Errorf("Load(%s, %d) = %+v, want the second save", kind, keys[0], loaded)
+		}
+
+		if err := s.Delete(kind, keys[0]); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Load(kind, keys[0], &loaded); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Load after Delete: got %v, want fs.ErrNotExist", err)
+		}
+		if err := s.Delete(kind, keys[0]); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("second Delete: got %v, want fs.ErrNotExist", err)
+		}
+	}
+}
+
+func TestSyncStateRoundTrip(t *testing.T) {
+	s := New(t.TempDir())
+	state, err := s.LoadSyncState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Issues != nil {
+		t.Errorf("fresh state = %+v, want empty", state)
+	}
+
+	synced := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	state.Issues = &synced
+	if err := s.SaveSyncState(state); err != nil {
+		t.Fatal(err)
+	}
+	state, err = s.LoadSyncState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Issues == nil || !state.Issues.Equal(synced) || state.PRs != nil {
+		t.Errorf("loaded state = %+v, want issues synced at %s", state, synced)
+	}
+}
@@ -0,0 +1,171 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// Actions recorded in the change feed.
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+)
+
+// changesDir is the directory under the output directory holding one change
+// log per sync run.
+const changesDir = "changes"
+
+// runIDFormat names a sync run after its start time.
+const runIDFormat = "20060102T150405Z"
+
+// Change is one record of the change feed: an item a sync run created,
+// updated or deleted. Fields lists the top-level JSON fields that differ
+// from the stored version; it is empty for created and deleted items.
+type Change struct {
+	RunID  string       `json:"run_id"`
+	Kind   storage.Kind `json:"kind"`
+	Number int          `json:"number"`
+	Action string       `json:"action"`
+	Fields []string     `json:"fields,omitempty"`
+}
+
+// changeFeed is a Backend that records each write that changes an item.
+// Records are appended to changes/<run-id>.jsonl under the output directory,
+// which is created on the first change, and to every extra writer. Saves
+// that leave an item as it was are not recorded.
+type changeFeed struct {
+	storage.Backend
+	runID string
+	dir   string
+	log   *os.File
+	out   []io.Writer
+
+	// notify, when set, is called with each change once it is recorded.
+	notify func(Change)
+}
+
+func newChangeFeed(store storage.Backend, outputDir, runID string, out ...io.Writer) *changeFeed {
+	return &changeFeed{Backend: store, runID: runID, dir: filepath.Join(outputDir, changesDir), out: out}
+}
+
+func (f *changeFeed) Save(kind storage.Kind, key int, data any) error {
+	var prev map[string]json.RawMessage
+	err := f.Backend.Load(kind, key, &prev)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	created := err != nil
+
+	var fields []string
+	if !created {
+		if fields, err = changedFields(prev, data); err != nil {
+			return err
+		}
+	}
+
+	if err := f.Backend.Save(kind, key, data); err != nil {
+		return err
+	}
+	switch {
+	case created:
+		return f.record(Change{Kind: kind, Number: key, Action: ActionCreated})
+	case len(fields) > 0:
+		return f.record(Change{Kind: kind, Number: key, Action: ActionUpdated, Fields: fields})
+	}
+	return nil
+}
+
+func (f *changeFeed) Delete(kind storage.Kind, key int) error {
+	if err := f.Backend.Delete(kind, key); err != nil {
+		return err
+	}
+	return f.record(Change{Kind: kind, Number: key, Action: ActionDeleted})
+}
+
+func (f *changeFeed) record(c Change) error {
+	c.RunID = f.runID
+	line, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if f.log == nil {
+		if err := os.MkdirAll(f.dir, 0755); err != nil {
+			return fmt.Errorf("failed to create changes directory: %w", err)
+		}
+		f.log, err = os.OpenFile(filepath.Join(f.dir, f.runID+".jsonl"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open change log: %w", err)
+		}
+	}
+	if _, err := f.log.Write(line); err != nil {
+		return fmt.Errorf("failed to write change log: %w", err)
+	}
+	for _, w := range f.out {
+		if _, err := w.Write(line); err != nil {
+			return fmt.Errorf("failed to write change: %w", err)
+		}
+	}
+	if f.notify != nil {
+		f.notify(c)
+	}
+	return nil
+}
+
+// Close closes the change log of the run, if any change was recorded.
+func (f *changeFeed) Close() error {
+	if f.log == nil {
+		return nil
+	}
+	err := f.log.Close()
+	f.log = nil
+	return err
+}
+
+// changedFields returns the sorted names of the top-level JSON fields of
+// data that were added, removed or changed compared to prev.
+func changedFields(prev map[string]json.RawMessage, data any) ([]string, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var cur map[string]json.RawMessage
+	if err := json.Unmarshal(content, &cur); err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for name, value := range cur {
+		old, ok := prev[name]
+		if !ok || !jsonEqual(old, value) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range prev {
+		if _, ok := cur[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// jsonEqual compares two JSON values regardless of their formatting.
+func jsonEqual(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
@@ -0,0 +1,122 @@
+package tracker
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestChangeFeed(t *testing.T) {
+	dir := t.TempDir()
+	var stream strings.Builder
+	var notified []Change
+	feed := newChangeFeed(storage.New(dir), dir, "20240101T000000Z", &stream)
+	feed.notify = func(c Change) { notified = append(notified, c) }
+
+	issue := github.Issue{Number: 1, Title: "Crash", State: "OPEN"}
+	steps := []func() error{
+		func() error { return feed.Save(storage.KindIssue, 1, issue) },
+		// Saving the same item again is not a change.
+		func() error { return feed.Save(storage.KindIssue, 1, issue) },
+		func() error {
+			issue.Title = "Crash on start"
+			issue.State = "CLOSED"
+			return feed.Save(storage.KindIssue, 1, issue)
+		},
+		func() error { return feed.Save(storage.KindPR, 2, github.PullRequest{Number: 2}) },
+		func() error { return feed.Delete(storage.KindPR, 2) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+	if err := feed.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Change{
+		{RunID: "20240101T000000Z", Kind: storage.KindIssue, Number: 1, Action: ActionCreated},
+		{RunID: "20240101T000000Z", Kind: storage.KindIssue, Number: 1, Action: ActionUpdated, Fields: []string{"state", "title"}},
+		{RunID: "20240101T000000Z", Kind: storage.KindPR, Number: 2, Action: ActionCreated},
+		{RunID: "20240101T000000Z", Kind: storage.KindPR, Number: 2, Action: ActionDeleted},
+	}
+
+	f, err := os.Open(filepath.Join(dir, changesDir, "20240101T000000Z.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var logged []Change
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var c Change
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			t.Fatalf("invalid change record %q: %v", scanner.Text(), err)
+		}
+		logged = append(logged, c)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(logged, want) {
+		t.Errorf("change log = %+v, want %+v", logged, want)
+	}
+	if !reflect.DeepEqual(notified, want) {
+		t.Errorf("notified = %+v, want %+v", notified, want)
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stream.String() != string(data) {
+		t.Errorf("streamed changes differ from the log:\n%s\nvs\n%s", stream.String(), data)
+	}
+}
+
+func TestChangeFeedWithoutChanges(t *testing.T) {
+	dir := t.TempDir()
+	feed := newChangeFeed(storage.New(dir), dir, "20240101T000000Z")
+	if err := feed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, changesDir)); !os.IsNotExist(err) {
+		t.Errorf("a run without changes created the changes directory: %v", err)
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	prev := map[string]json.RawMessage{
+		"title":  json.RawMessage(`"Crash"`),
+		"labels": json.RawMessage(`[ "bug" ]`),
+		"body":   json.RawMessage(`"gone"`),
+	}
+	tests := []struct {
+		name string
+		data any
+		want []string
+	}{
+		{"formatting only", map[string]any{"title": "Crash", "labels": []string{"bug"}, "body": "gone"}, nil},
+		{"changed", map[string]any{"title": "Crash!", "labels": []string{"bug"}, "body": "gone"}, []string{"title"}},
+		{"added and removed", map[string]any{"title": "Crash", "labels": []string{"bug"}, "state": "OPEN"}, []string{"body", "state"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changedFields(prev, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedFields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
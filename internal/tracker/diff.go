@@ -0,0 +1,361 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// itemVersion is the part of an issue, pull request or discussion that
+// PrintItemDiff compares.
+type itemVersion struct {
+	Title    string
+	State    string
+	Body     string
+	Labels   []string
+	Comments []versionComment
+}
+
+type versionComment struct {
+	ID     string
+	Author string
+	Body   string
+}
+
+// diffKinds are the kinds whose versions can be compared, in the order they
+// are looked up. They share one number space.
+var diffKinds = []storage.Kind{storage.KindIssue, storage.KindPR, storage.KindDiscussion}
+
+// PrintItemDiff writes what changed in an item between the versions that
+// were current at from and at to, read from the history kept by syncs with
+// History enabled.
+func PrintItemDiff(w io.Writer, outputDir, owner, repo string, number int, from, to time.Time) error {
+	store := storage.New(outputDir)
+
+	kind, err := findVersionedItem(store, number)
+	if err != nil {
+		return err
+	}
+	if kind == "" {
+		return fmt.Errorf("no history for item %d in %s (sync with --history to record it)", number, outputDir)
+	}
+	old, err := loadItemVersion(store, kind, number, from)
+	if err != nil {
+		return err
+	}
+	cur, err := loadItemVersion(store, kind, number, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s/%s#%d (%s) %s..%s\n", owner, repo, number, kind, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if !writeVersionDiff(w, old, cur) {
+		fmt.Fprintln(w, "No changes.")
+	}
+	return nil
+}
+
+// findVersionedItem returns the kind of the item with recorded versions, or
+// "" if there is none.
+func findVersionedItem(store *storage.Storage, number int) (storage.Kind, error) {
+	for _, kind := range diffKinds {
+		versions, err := store.Versions(kind, number)
+		if err != nil {
+			return "", fmt.Errorf("failed to list versions of %s %d: %w", kind, number, err)
+		}
+		if len(versions) > 0 {
+			return kind, nil
+		}
+	}
+	return "", nil
+}
+
+func loadItemVersion(store *storage.Storage, kind storage.Kind, number int, at time.Time) (*itemVersion, error) {
+	var v *itemVersion
+	var err error
+	switch kind {
+	case storage.KindIssue:
+		var issue github.Issue
+		if err = store.LoadVersion(kind, number, at, &issue); err == nil {
+			v = &itemVersion{Title: issue.Title, State: issue.State, Body: issue.Body, Labels: labelNames(issue.Labels)}
+			v.addComments(issue.Comments)
+		}
+	case storage.KindPR:
+		var pr github.PullRequest
+		if err = store.LoadVersion(kind, number, at, &pr); err == nil {
+			v = &itemVersion{Title: pr.Title, State: pr.State, Body: pr.Body, Labels: labelNames(pr.Labels)}
+			v.addComments(pr.Comments)
+		}
+	case storage.KindDiscussion:
+		var disc github.Discussion
+		if err = store.LoadVersion(kind, number, at, &disc); err == nil {
+			state := "OPEN"
+			if disc.Closed {
+				state = "CLOSED"
+			}
+			v = &itemVersion{Title: disc.Title, State: state, Body: disc.Body, Labels: labelNames(disc.Labels)}
+			for _, c := range disc.Comments {
+				v.Comments = append(v.Comments, versionComment{ID: c.ID, Author: c.Author.Login, Body: c.Body})
+				for _, r := range c.Replies {
+					v.Comments = append(v.Comments, versionComment{ID: r.ID, Author: r.Author.Login, Body: r.Body})
+				}
+			}
+		}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no version of %s %d recorded at %s", kind, number, at.UTC().Format(time.RFC3339))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s %d: %w", kind, number, err)
+	}
+	return v, nil
+}
+
+func (v *itemVersion) addComments(comments []github.Comment) {
+	for _, c := range comments {
+		v.Comments = append(v.Comments, versionComment{ID: c.ID, Author: c.Author.Login, Body: c.Body})
+	}
+}
+
+// writeVersionDiff prints the differences between two versions and reports
+// whether there were any.
+func writeVersionDiff(w io.Writer, old, cur *itemVersion) bool {
+	changed := false
+	if old.Title != cur.Title {
+		fmt.Fprintf(w, "Title: %s -> %s\n", strconv.Quote(old.Title), strconv.Quote(cur.Title))
+		changed = true
+	}
+	if old.State != cur.State {
+		fmt.Fprintf(w, "State: %s -> %s\n", old.State, cur.State)
+		changed = true
+	}
+
+	var labels []string
+	for _, l := range cur.Labels {
+		if !containsString(old.Labels, l) {
+			labels = append(labels, "+"+l)
+		}
+	}
+	for _, l := range old.Labels {
+		if !containsString(cur.Labels, l) {
+			labels = append(labels, "-"+l)
+		}
+	}
+	if len(labels) > 0 {
+		fmt.Fprintf(w, "Labels: %s\n", strings.Join(labels, " "))
+		changed = true
+	}
+
+	if old.Body != cur.Body {
+		fmt.Fprintln(w, "Body:")
+		writeLineDiff(w, old.Body, cur.Body, "  ")
+		changed = true
+	}
+
+	oldComments := map[string]versionComment{}
+	for _, c := range old.Comments {
+		oldComments[c.ID] = c
+	}
+	curIDs := map[string]bool{}
+	var comments []string
+	for _, c := range cur.Comments {
+		curIDs[c.ID] = true
+		prev, ok := oldComments[c.ID]
+		switch {
+		case !ok:
+			comments = append(comments, fmt.Sprintf("  + %s by @%s\n", c.ID, c.Author)+indent(c.Body, "      "))
+		case prev.Body != c.Body:
+			var b strings.Builder
+			fmt.Fprintf(&b, "  ~ %s by @%s\n", c.ID, c.Author)
+			writeLineDiff(&b, prev.Body, c.Body, "    ")
+			comments = append(comments, b.String())
+		}
+	}
+	for _, c := range old.Comments {
+		if !curIDs[c.ID] {
+			comments = append(comments, fmt.Sprintf("  - %s by @%s\n", c.ID, c.Author))
+		}
+	}
+	if len(comments) > 0 {
+		fmt.Fprintln(w, "Comments:")
+		for _, c := range comments {
+			fmt.Fprint(w, c)
+		}
+		changed = true
+	}
+	return changed
+}
+
+// writeLineDiff prints both texts merged line by line, marking lines only
+// in the old text with "-" and lines only in the new one with "+".
+func writeLineDiff(w io.Writer, old, cur, prefix string) {
+	// Within a run of changed lines, removals are printed before additions.
+	var added []string
+	flush := func() {
+		for _, line := range added {
+			fmt.Fprintf(w, "%s+ %s\n", prefix, line)
+		}
+		added = added[:0]
+	}
+	diffLines(splitLines(old), splitLines(cur), func(op byte, line string) {
+		switch op {
+		case '+':
+			added = append(added, line)
+		case '-':
+			fmt.Fprintf(w, "%s- %s\n", prefix, line)
+		default:
+			flush()
+			fmt.Fprintf(w, "%s  %s\n", prefix, line)
+		}
+	})
+	flush()
+}
+
+// diffLines calls emit for each line of a shortest edit script turning a
+// into b, in order: ' ' for common lines, '-' for lines only in a and '+'
+// for lines only in b. It uses Myers' linear space refinement, splitting
+// both sides at the middle snake of an optimal path and recursing, so long
+// bodies need memory proportional to their length rather than its square.
+func diffLines(a, b []string, emit func(op byte, line string)) {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		emit(' ', a[n])
+		n++
+	}
+	a, b = a[n:], b[n:]
+
+	m := 0
+	for m < len(a) && m < len(b) && a[len(a)-1-m] == b[len(b)-1-m] {
+		m++
+	}
+	suffix := a[len(a)-m:]
+	a, b = a[:len(a)-m], b[:len(b)-m]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			emit('+', line)
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			emit('-', line)
+		}
+	default:
+		x, y := middleSnake(a, b)
+		diffLines(a[:x], b[:y], emit)
+		diffLines(a[x:], b[y:], emit)
+	}
+
+	for _, line := range suffix {
+		emit(' ', line)
+	}
+}
+
+// middleSnake returns a point on a shortest edit path from a to b, found by
+// searching forward from the start and backward from the end until the
+// searches meet. a and b must be non-empty and differ in their first and
+// last lines, so the point splits both into smaller problems.
+func middleSnake(a, b []string) (x, y int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+
+	// forward[k] and backward[k] hold the furthest x reached on diagonal
+	// k = x - y, offset so that every diagonal either search visits is a
+	// valid index.
+	offset := limit + abs(delta) + 1
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	forward[offset+1] = 0
+	backward[offset+delta-1] = n
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			i := offset + k
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y = x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+			if odd && k >= delta-(d-1) && k <= delta+(d-1) && x >= backward[i] {
+				return startX, startY
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			kk := k + delta
+			i := offset + kk
+			if k == d || (k != -d && backward[i-1] < backward[i+1]) {
+				x = backward[i-1]
+			} else {
+				x = backward[i+1] - 1
+			}
+			y = x - kk
+			for x > 0 && y > 0 && a[x-1] == b[y-1] {
+				x--
+				y--
+			}
+			backward[i] = x
+			if !odd && kk >= -d && kk <= d && x <= forward[i] {
+				return x, y
+			}
+		}
+	}
+	// The searches always meet within limit steps. Splitting off the first
+	// line would still yield a correct, if longer, script.
+	return 1, 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func indent(s, prefix string) string {
+	var b strings.Builder
+	for _, line := range splitLines(s) {
+		b.WriteString(prefix + line + "\n")
+	}
+	return b.String()
+}
+
+func labelNames(labels []github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package tracker
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// lcsLength is the quadratic longest common subsequence that diffLines
+// must match: a shortest edit script keeps exactly that many lines.
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				cur[j] = prev[j+1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j+1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[0]
+}
+
+func TestDiffLinesRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for iter := 0; iter < 2000; iter++ {
+		a, b := randomLines(), randomLines()
+		var oldLines, newLines []string
+		kept := 0
+		diffLines(a, b, func(op byte, line string) {
+			switch op {
+			case ' ':
+				oldLines = append(oldLines, line)
+				newLines = append(newLines, line)
+				kept++
+			case '-':
+				oldLines = append(oldLines, line)
+			case '+':
+				newLines = append(newLines, line)
+			}
+		})
+
+		if strings.Join(oldLines, ",") != strings.Join(a, ",") || strings.Join(newLines, ",") != strings.Join(b, ",") {
+			t.Fatalf("diff of %q and %q does not reproduce them: got %q and %q", a, b, oldLines, newLines)
+		}
+		if want := lcsLength(a, b); kept != want {
+			t.Fatalf("diff of %q and %q keeps %d lines, want %d", a, b, kept, want)
+		}
+	}
+}
+
+func TestWriteLineDiff(t *testing.T) {
+	var out strings.Builder
+	writeLineDiff(&out, "one\ntwo\nthree\n", "one\r\n2\r\nthree\r\nfour", "  ")
+	want := "    one\n  - two\n  + 2\n    three\n  + four\n"
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestWriteVersionDiff(t *testing.T) {
+	old := &itemVersion{
+		Title:  "Crash",
+		State:  "OPEN",
+		Body:   "It crashes.",
+		Labels: []string{"bug", "triage"},
+		Comments: []versionComment{
+			{ID: "c1", Author: "mona", Body: "Same here"},
+			{ID: "c2", Author: "hubot", Body: "spam"},
+		},
+	}
+	cur := &itemVersion{
+		Title:  "Crash on start",
+		State:  "CLOSED",
+		Body:   "It crashes.",
+		Labels: []string{"bug", "fixed"},
+		Comments: []versionComment{
+			{ID: "c1", Author: "mona", Body: "Same here\nOn Linux too"},
+			{ID: "c3", Author: "octocat", Body: "Fixed"},
+		},
+	}
+
+	var out strings.Builder
+	if !writeVersionDiff(&out, old, cur) {
+		t.Fatal("writeVersionDiff reported no changes")
+	}
+	want := `Title: "Crash" -> "Crash on start"
+State: OPEN -> CLOSED
+Labels: +fixed -triage
+Comments:
+  ~ c1 by @mona
+      Same here
+    + On Linux too
+  + c3 by @octocat
+      Fixed
+  - c2 by @hubot
+`
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if writeVersionDiff(&out, old, old) || out.Len() != 0 {
+		t.Errorf("identical versions reported changes: %q", out.String())
+	}
+}
+
+func TestPrintItemDiff(t *testing.T) {
+	dir := t.TempDir()
+	path, err := storage.ItemPath(storage.KindIssue, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := filepath.Join(dir, filepath.Dir(path), ".history", "7")
+	if err := os.MkdirAll(history, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, title := range map[string]string{
+		"20240101T000000Z.json": "old",
+		"20240201T000000Z.json": "new",
+	} {
+		if err := os.WriteFile(filepath.Join(history, name), []byte(`{"number":7,"title":"`+title+`"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+
+	var out strings.Builder
+	if err := PrintItemDiff(&out, dir, "o", "r", 7, from, to); err != nil {
+		t.Fatal(err)
+	}
+	want := "o/r#7 (issue) 2024-01-15T00:00:00Z..2024-02-15T00:00:00Z\nTitle: \"old\" -> \"new\"\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if err := PrintItemDiff(&out, dir, "o", "r", 7, to, to); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "No changes.\n") {
+		t.Errorf("same version: got %q", out.String())
+	}
+
+	if err := PrintItemDiff(&out, dir, "o", "r", 7, from.AddDate(-1, 0, 0), to); err == nil {
+		t.Error("expected an error for a time before the first version")
+	}
+	if err := PrintItemDiff(&out, dir, "o", "r", 8, from, to); err == nil || !strings.Contains(err.Error(), "no history") {
+		t.Errorf("item without history: got %v", err)
+	}
+}
+
+func TestDiffLinesLongInput(t *testing.T) {
+	// The quadratic table this replaced needed 10^10 cells here.
+	a := make([]string, 100000)
+	b := make([]string, 100000)
+	for i := range a {
+		a[i] = "line"
+		b[i] = "line"
+	}
+	a[500] = "old"
+	b[99500] = "new"
+
+	var changed []string
+	diffLines(a, b, func(op byte, line string) {
+		if op != ' ' {
+			changed = append(changed, string(op)+line)
+		}
+	})
+	if strings.Join(changed, " ") != "-old +new" && strings.Join(changed, " ") != "+new -old" {
+		t.Errorf("changed lines = %v", changed)
+	}
+}
@@ -0,0 +1,75 @@
+package tracker
+
+import (
+	"sort"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+)
+
+// mergeDiscussionEvents carries the events recorded by earlier syncs over to
+// disc and appends the changes observed since the previous copy was saved.
+// GitHub does not say who made these changes or exactly when, so observed
+// events have no actor, are dated with the discussion's update time and are
+// marked with an "observed" source detail.
+func mergeDiscussionEvents(prev, disc *github.Discussion) {
+	events := append([]github.Event(nil), prev.Events...)
+	for _, e := range disc.Events {
+		if !containsEvent(events, e) {
+			events = append(events, e)
+		}
+	}
+	events = append(events, observedDiscussionEvents(prev, disc)...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	disc.Events = events
+}
+
+// observedSource is the source detail of events inferred by comparing
+// discussion snapshots rather than reported by GitHub.
+const observedSource = "observed"
+
+func observedDiscussionEvents(prev, disc *github.Discussion) []github.Event {
+	var events []github.Event
+	observe := func(eventType string, details map[string]string) {
+		if details == nil {
+			details = map[string]string{}
+		}
+		details["source"] = observedSource
+		events = append(events, github.Event{Type: eventType, CreatedAt: disc.UpdatedAt, Details: details})
+	}
+
+	if prev.Title != disc.Title {
+		observe("renamed", map[string]string{"from": prev.Title, "to": disc.Title})
+	}
+	if prev.Category != disc.Category {
+		observe("category_changed", map[string]string{"from": prev.Category, "to": disc.Category})
+	}
+	if prev.Locked != disc.Locked {
+		if disc.Locked {
+			observe("locked", nil)
+		} else {
+			observe("unlocked", nil)
+		}
+	}
+	if prev.Closed && !disc.Closed {
+		observe("reopened", nil)
+	}
+	if prev.Pinned && !disc.Pinned {
+		observe("unpinned", nil)
+	}
+	if prev.Answer != nil && (disc.Answer == nil || disc.Answer.CommentID != prev.Answer.CommentID) {
+		observe("answer_unmarked", map[string]string{"comment_id": prev.Answer.CommentID})
+	}
+	return events
+}
+
+func containsEvent(events []github.Event, e github.Event) bool {
+	for _, existing := range events {
+		if existing.Type == e.Type && existing.CreatedAt.Equal(e.CreatedAt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package tracker
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+)
+
+func TestMergeDiscussionEvents(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	t2 := t0.Add(2 * time.Hour)
+	pinned := github.Event{Type: "pinned", Actor: github.Actor{Login: "mona"}, CreatedAt: t0}
+
+	prev := github.Discussion{
+		Title:    "old",
+		Category: "Q&A",
+		Pinned:   true,
+		Answer:   &github.DiscussionAnswer{CommentID: "DC_1"},
+		Events:   []github.Event{pinned},
+	}
+	disc := github.Discussion{
+		Title:     "new",
+		Category:  "Q&A",
+		Locked:    true,
+		UpdatedAt: t2,
+		Events:    []github.Event{pinned, {Type: "closed", CreatedAt: t1}},
+	}
+	mergeDiscussionEvents(&prev, &disc)
+
+	var types []string
+	for _, e := range disc.Events {
+		types = append(types, e.Type)
+	}
+	want := "pinned closed renamed locked unpinned answer_unmarked"
+	if got := strings.Join(types, " "); got != want {
+		t.Fatalf("got events %q, want %q", got, want)
+	}
+
+	for _, e := range disc.Events[2:] {
+		details := e.Details.(map[string]string)
+		if details["source"] != "observed" {
+			t.Errorf("%s: source = %q, want observed", e.Type, details["source"])
+		}
+		if !e.CreatedAt.Equal(t2) {
+			t.Errorf("%s: CreatedAt = %v, want %v", e.Type, e.CreatedAt, t2)
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), `"actor"`) {
+			t.Errorf("%s: observed event has an actor: %s", e.Type, data)
+		}
+	}
+	renamed := disc.Events[2].Details.(map[string]string)
+	if renamed["from"] != "old" || renamed["to"] != "new" {
+		t.Errorf("renamed details = %v", renamed)
+	}
+}
+
+func TestMergeDiscussionEventsUnchanged(t *testing.T) {
+	prev := github.Discussion{Title: "same", Closed: true}
+	disc := prev
+	mergeDiscussionEvents(&prev, &disc)
+	if len(disc.Events) != 0 {
+		t.Errorf("got events %+v, want none", disc.Events)
+	}
+}
@@ -0,0 +1,124 @@
+package tracker
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// Commits are made under a fixed identity and dated with the sync time, so
+// the history only differs where the synced data does.
+const (
+	gitAuthorName  = "gh-dumpster"
+	gitAuthorEmail = "gh-dumpster@localhost"
+)
+
+// gitMaxListed caps the items listed per section of a commit message.
+const gitMaxListed = 50
+
+// commitOutputDir commits everything in dir to its git repository, creating
+// the repository on first use. The message summarises the items created,
+// updated and removed since the previous commit.
+func commitOutputDir(dir, owner, repo string, syncTime time.Time) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if _, err := runGit(dir, nil, "init", "-q"); err != nil {
+			return err
+		}
+	}
+	if _, err := runGit(dir, nil, "add", "-A"); err != nil {
+		return err
+	}
+
+	out, err := runGit(dir, nil, "diff", "--cached", "--name-status", "--no-renames", "-z")
+	if err != nil {
+		return err
+	}
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	if len(fields) < 2 {
+		fmt.Println("No changes to commit")
+		return nil
+	}
+
+	var created, updated, removed []string
+	for i := 0; i+1 < len(fields); i += 2 {
+		kind, key, ok := storage.ItemAt(fields[i+1])
+		if !ok {
+			continue
+		}
+		item := describeItem(kind, key)
+		switch fields[i] {
+		case "A":
+			created = append(created, item)
+		case "D":
+			removed = append(removed, item)
+		default:
+			updated = append(updated, item)
+		}
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Sync %s/%s: %d created, %d updated, %d removed\n", owner, repo, len(created), len(updated), len(removed))
+	writeItemList(&msg, "Created", created)
+	writeItemList(&msg, "Updated", updated)
+	writeItemList(&msg, "Removed", removed)
+
+	date := syncTime.UTC().Format(time.RFC3339)
+	env := []string{
+		"GIT_AUTHOR_NAME=" + gitAuthorName,
+		"GIT_AUTHOR_EMAIL=" + gitAuthorEmail,
+		"GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=" + gitAuthorName,
+		"GIT_COMMITTER_EMAIL=" + gitAuthorEmail,
+		"GIT_COMMITTER_DATE=" + date,
+	}
+	if _, err := runGit(dir, env, "commit", "-q", "--no-gpg-sign", "-m", msg.String()); err != nil {
+		return err
+	}
+	fmt.Printf("Committed %d created, %d updated, %d removed items\n", len(created), len(updated), len(removed))
+	return nil
+}
+
+func describeItem(kind storage.Kind, key int) string {
+	switch kind {
+	case storage.KindRepository:
+		return "repository metadata"
+	case storage.KindIssue, storage.KindPR, storage.KindDiscussion, storage.KindProject:
+		return fmt.Sprintf("%s #%d", kind, key)
+	}
+	return fmt.Sprintf("%s %d", kind, key)
+}
+
+func writeItemList(msg *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(msg, "\n%s:\n", title)
+	for i, item := range items {
+		if i == gitMaxListed {
+			fmt.Fprintf(msg, "- ... and %d more\n", len(items)-gitMaxListed)
+			break
+		}
+		fmt.Fprintf(msg, "- %s\n", item)
+	}
+}
+
+// runGit runs git in dir with extra environment variables and returns its
+// standard output.
+func runGit(dir string, env []string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
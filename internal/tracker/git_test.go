@@ -0,0 +1,107 @@
+package tracker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestCommitOutputDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	store := storage.New(dir)
+	t0 := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(kind storage.Kind, key int, item any) {
+		t.Helper()
+		if err := store.Save(kind, key, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lastCommit := func() (message, author, date string) {
+		t.Helper()
+		out, err := runGit(dir, nil, "log", "-1", "--format=%an <%ae>%n%at%n%B")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.SplitN(out, "\n", 3)
+		return strings.TrimSpace(lines[2]), lines[0], lines[1]
+	}
+
+	save(storage.KindIssue, 1, &github.Issue{Number: 1, Title: "one"})
+	save(storage.KindPR, 2, &github.PullRequest{Number: 2, Title: "two"})
+	save(storage.KindRepository, 0, &github.Repository{NameWithOwner: "octo/repo"})
+	if err := store.SaveSyncState(&storage.SyncState{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitOutputDir(dir, "octo", "repo", t0); err != nil {
+		t.Fatalf("first commit: %v", err)
+	}
+
+	msg, author, date := lastCommit()
+	want := "Sync octo/repo: 3 created, 0 updated, 0 removed\n\nCreated:\n- issue #1\n- pr #2\n- repository metadata"
+	if msg != want {
+		t.Errorf("message = %q, want %q", msg, want)
+	}
+	if author != gitAuthorName+" <"+gitAuthorEmail+">" {
+		t.Errorf("author = %q", author)
+	}
+	if date != fmt.Sprint(t0.Unix()) {
+		t.Errorf("date = %q, want the sync time", date)
+	}
+
+	save(storage.KindIssue, 1, &github.Issue{Number: 1, Title: "one, renamed"})
+	if err := store.Delete(storage.KindPR, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := commitOutputDir(dir, "octo", "repo", t0.Add(time.Hour)); err != nil {
+		t.Fatalf("second commit: %v", err)
+	}
+	msg, _, _ = lastCommit()
+	want = "Sync octo/repo: 0 created, 1 updated, 1 removed\n\nUpdated:\n- issue #1\n\nRemoved:\n- pr #2"
+	if msg != want {
+		t.Errorf("message = %q, want %q", msg, want)
+	}
+
+	// Nothing changed, so nothing is committed.
+	if err := commitOutputDir(dir, "octo", "repo", t0.Add(2*time.Hour)); err != nil {
+		t.Fatalf("empty commit: %v", err)
+	}
+	out, err := runGit(dir, nil, "rev-list", "--count", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out) != "2" {
+		t.Errorf("got %s commits, want 2", strings.TrimSpace(out))
+	}
+}
+
+func TestWriteItemList(t *testing.T) {
+	var items []string
+	for i := range gitMaxListed + 3 {
+		items = append(items, fmt.Sprintf("issue #%d", i))
+	}
+	var msg strings.Builder
+	writeItemList(&msg, "Created", items)
+
+	lines := strings.Split(strings.TrimSpace(msg.String()), "\n")
+	if len(lines) != gitMaxListed+2 {
+		t.Fatalf("got %d lines, want %d", len(lines), gitMaxListed+2)
+	}
+	if lines[0] != "Created:" || lines[len(lines)-1] != "- ... and 3 more" {
+		t.Errorf("unexpected list:\n%s", msg.String())
+	}
+
+	msg.Reset()
+	writeItemList(&msg, "Removed", nil)
+	if msg.Len() != 0 {
+		t.Errorf("empty list wrote %q", msg.String())
+	}
+}
@@ -0,0 +1,174 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// DefaultHookJobs is the number of on-change hooks run at once when
+// SyncOptions.HookJobs is not set.
+const DefaultHookJobs = 4
+
+// hookRunner runs the commands configured for changed items. On-change
+// hooks start as soon as an item's save succeeds and run in the background,
+// at most jobs at a time; the on-complete hook runs once at the end of the
+// sync with every change of the run.
+type hookRunner struct {
+	onChange   string
+	onComplete string
+	outputDir  string
+	runID      string
+
+	sem chan struct{}
+	wg  sync.WaitGroup
+
+	mu       sync.Mutex
+	changes  []Change
+	failures int
+}
+
+func newHookRunner(onChange, onComplete, outputDir, runID string, jobs int) *hookRunner {
+	if jobs <= 0 {
+		jobs = DefaultHookJobs
+	}
+	return &hookRunner{
+		onChange:   onChange,
+		onComplete: onComplete,
+		outputDir:  outputDir,
+		runID:      runID,
+		sem:        make(chan struct{}, jobs),
+	}
+}
+
+// changed records a change and starts its on-change hook. It blocks while
+// the maximum number of hooks is running.
+func (h *hookRunner) changed(c Change) {
+	h.mu.Lock()
+	h.changes = append(h.changes, c)
+	h.mu.Unlock()
+
+	if h.onChange == "" {
+		return
+	}
+	path, err := itemFile(h.outputDir, c)
+	if err != nil {
+		h.fail(fmt.Sprintf("on-change hook for %s", describeItem(c.Kind, c.Number)), err, nil)
+		return
+	}
+	command := expandHook(h.onChange, map[string]string{
+		"kind":   string(c.Kind),
+		"number": strconv.Itoa(c.Number),
+		"path":   path,
+		"action": c.Action,
+	})
+
+	h.sem <- struct{}{}
+	h.wg.Add(1)
+	go func() {
+		defer func() {
+			<-h.sem
+			h.wg.Done()
+		}()
+		h.run(fmt.Sprintf("on-change hook for %s", describeItem(c.Kind, c.Number)), command, nil)
+	}()
+}
+
+// wait blocks until every on-change hook has finished.
+func (h *hookRunner) wait() {
+	h.wg.Wait()
+}
+
+// complete waits for the on-change hooks and then runs the on-complete
+// hook, if the run changed anything. The hook gets the run's change records
+// as JSON Lines on its standard input. It returns an error when any hook
+// failed.
+func (h *hookRunner) complete() error {
+	h.wait()
+
+	if h.onComplete != "" && len(h.changes) > 0 {
+		var stdin bytes.Buffer
+		enc := json.NewEncoder(&stdin)
+		for _, c := range h.changes {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+		}
+		changes, err := filepath.Abs(filepath.Join(h.outputDir, changesDir, h.runID+".jsonl"))
+		if err != nil {
+			return err
+		}
+		command := expandHook(h.onComplete, map[string]string{
+			"run_id":  h.runID,
+			"changes": changes,
+			"count":   strconv.Itoa(len(h.changes)),
+		})
+		h.run("on-complete hook", command, &stdin)
+	}
+
+	if h.failures > 0 {
+		return fmt.Errorf("hooks failed: %d", h.failures)
+	}
+	return nil
+}
+
+// run runs a hook command through the shell from the output directory. Its
+// output is printed once it exits, so output of concurrent hooks is not
+// interleaved.
+func (h *hookRunner) run(name, command string, stdin *bytes.Buffer) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = h.outputDir
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		h.fail(name, err, out)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	os.Stdout.Write(out)
+}
+
+func (h *hookRunner) fail(name string, err error, out []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures++
+	fmt.Fprintf(os.Stderr, "%s failed: %v\n", name, err)
+	if out := strings.TrimSpace(string(out)); out != "" {
+		fmt.Fprintln(os.Stderr, out)
+	}
+}
+
+// itemFile returns the absolute path of a changed item's JSON file.
+func itemFile(outputDir string, c Change) (string, error) {
+	path, err := storage.ItemPath(c.Kind, c.Number)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(filepath.Join(outputDir, path))
+}
+
+// expandHook replaces the {name} placeholders of a hook command with
+// shell-quoted values.
+func expandHook(command string, values map[string]string) string {
+	var pairs []string
+	for name, value := range values {
+		pairs = append(pairs, "{"+name+"}", shellQuote(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(command)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,106 @@
+package tracker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestExpandHookQuoting(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"plain", "issues/12/123.json"},
+		{"spaces", "my dump/issues/12/123.json"},
+		{"single quote", "it's"},
+		{"quotes only", `'"'`},
+		{"command substitution", "$(touch pwned)"},
+		{"backticks", "`touch pwned`"},
+		{"variable", "$HOME ${PATH}"},
+		{"newline", "line one\nline two"},
+		{"separators", "a; touch pwned && b | c"},
+		{"empty", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			command := expandHook(`printf '%s' {path}`, map[string]string{"path": tt.value})
+			cmd := exec.Command("sh", "-c", command)
+			cmd.Dir = dir
+			out, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("%s: %v", command, err)
+			}
+			if string(out) != tt.value {
+				t.Errorf("%s printed %q, want %q", command, out, tt.value)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "pwned")); err == nil {
+				t.Errorf("%s ran an injected command", command)
+			}
+		})
+	}
+}
+
+func TestExpandHook(t *testing.T) {
+	got := expandHook("notify {kind} {number} {action} {unknown} {kind}", map[string]string{
+		"kind":   "issue",
+		"number": "12",
+		"action": "updated",
+	})
+	want := "notify 'issue' '12' 'updated' {unknown} 'issue'"
+	if got != want {
+		t.Errorf("expandHook = %q, want %q", got, want)
+	}
+}
+
+func TestHookRunner(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+	dir := t.TempDir()
+	h := newHookRunner(
+		"echo {action} {kind} {number} >> hooks.log",
+		"cat > complete.log; echo {count} {run_id} >> complete.log",
+		dir, "20240101T000000Z", 2,
+	)
+	h.changed(Change{RunID: "20240101T000000Z", Kind: storage.KindIssue, Number: 1, Action: ActionCreated})
+	h.changed(Change{RunID: "20240101T000000Z", Kind: storage.KindPR, Number: 2, Action: ActionDeleted})
+	if err := h.complete(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "hooks.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !containsString(lines, "created issue 1") || !containsString(lines, "deleted pr 2") {
+		t.Errorf("on-change hooks wrote %q", data)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "complete.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"run_id":"20240101T000000Z","kind":"issue","number":1,"action":"created"}
+{"run_id":"20240101T000000Z","kind":"pr","number":2,"action":"deleted"}
+2 20240101T000000Z
+`
+	if string(data) != want {
+		t.Errorf("on-complete hook wrote %q, want %q", data, want)
+	}
+
+	failing := newHookRunner("exit 1", "", dir, "20240101T000000Z", 1)
+	failing.changed(Change{Kind: storage.KindIssue, Number: 1, Action: ActionUpdated})
+	if err := failing.complete(); err == nil {
+		t.Error("complete did not report the failed hook")
+	}
+}
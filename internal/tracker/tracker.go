@@ -2,7 +2,11 @@ package tracker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
 	"time"
 
 	"github.com/itaysk/gh-dumpster/internal/github"
@@ -10,13 +14,47 @@ import (
 )
 
 type SyncOptions struct {
-	Owner       string
-	Repo        string
-	OutputDir   string
-	Issues      bool
-	PRs         bool
-	Discussions bool
-	Since       *time.Time
+	Owner          string
+	Repo           string
+	OutputDir      string
+	Issues         bool
+	PRs            bool
+	Discussions    bool
+	Repository     bool
+	Releases       bool
+	Projects       bool
+	WorkflowRuns   bool
+	CommitComments bool
+	Since          *time.Time
+
+	// SQLitePath, when set, mirrors every synced item into a SQLite
+	// database alongside the JSON tree.
+	SQLitePath string
+
+	// Git makes OutputDir a git repository and commits it after the sync.
+	Git bool
+
+	// History keeps every version of the synced items in the JSON tree, so
+	// they can be compared with PrintItemDiff.
+	History bool
+
+	// ChangesOut, when set, is a file or named pipe that also receives the
+	// change feed of the run as it is written.
+	ChangesOut string
+
+	// OnChange is a shell command run for every item the sync created,
+	// updated or deleted, with {kind}, {number}, {path} and {action}
+	// replaced. OnComplete is run once after the sync if anything changed,
+	// with {run_id}, {changes} and {count} replaced and the change records
+	// on its standard input. HookJobs bounds the number of on-change hooks
+	// running at once.
+	OnChange   string
+	OnComplete string
+	HookJobs   int
+
+	// Backend receives the synced items. When nil, items are written as a
+	// JSON tree under OutputDir.
+	Backend storage.Backend
 }
 
 func Sync(opts SyncOptions) error {
@@ -25,9 +63,28 @@ func Sync(opts SyncOptions) error {
 		return err
 	}
 
-	store := storage.New(opts.OutputDir)
-	if err := store.EnsureDirs(); err != nil {
-		return fmt.Errorf("failed to create output directories: %w", err)
+	store := opts.Backend
+	if store == nil {
+		jsonStore := storage.New(opts.OutputDir)
+		if err := jsonStore.EnsureDirs(); err != nil {
+			return fmt.Errorf("failed to create output directories: %w", err)
+		}
+		if opts.History {
+			jsonStore.EnableHistory()
+		}
+		store = jsonStore
+
+		if opts.SQLitePath != "" {
+			db, err := storage.OpenSQLite(opts.SQLitePath)
+			if err != nil {
+				return fmt.Errorf("failed to open SQLite database: %w", err)
+			}
+			defer db.Close()
+			if err := backfillSQLite(db, jsonStore); err != nil {
+				return fmt.Errorf("failed to backfill SQLite database: %w", err)
+			}
+			store = storage.Mirror(jsonStore, db)
+		}
 	}
 
 	state, err := store.LoadSyncState()
@@ -38,6 +95,24 @@ func Sync(opts SyncOptions) error {
 	ctx := context.Background()
 	syncTime := time.Now()
 
+	var changesOut []io.Writer
+	if opts.ChangesOut != "" {
+		f, err := os.OpenFile(opts.ChangesOut, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open changes output: %w", err)
+		}
+		defer f.Close()
+		changesOut = append(changesOut, f)
+	}
+	runID := syncTime.UTC().Format(runIDFormat)
+	feed := newChangeFeed(store, opts.OutputDir, runID, changesOut...)
+	defer feed.Close()
+	store = feed
+
+	hooks := newHookRunner(opts.OnChange, opts.OnComplete, opts.OutputDir, runID, opts.HookJobs)
+	defer hooks.wait()
+	feed.notify = hooks.changed
+
 	// Use --since flag if provided, otherwise use stored state
 	getSince := func(stored *time.Time) *time.Time {
 		if opts.Since != nil {
@@ -47,14 +122,14 @@ func Sync(opts SyncOptions) error {
 	}
 
 	if opts.Issues {
-		if err := syncIssues(ctx, client, store, opts.Owner, opts.Repo, getSince(state.Issues)); err != nil {
+		if err := syncIssues(ctx, client, store, opts.Owner, opts.Repo, getSince(state.Issues), opts.Projects); err != nil {
 			return fmt.Errorf("failed to sync issues: %w", err)
 		}
 		state.Issues = &syncTime
 	}
 
 	if opts.PRs {
-		if err := syncPRs(ctx, client, store, opts.Owner, opts.Repo, getSince(state.PRs)); err != nil {
+		if err := syncPRs(ctx, client, store, opts.Owner, opts.Repo, getSince(state.PRs), opts.Projects); err != nil {
 			return fmt.Errorf("failed to sync pull requests: %w", err)
 		}
 		state.PRs = &syncTime
@@ -67,14 +142,83 @@ func Sync(opts SyncOptions) error {
 		state.Discussions = &syncTime
 	}
 
+	if opts.Repository {
+		if err := syncRepository(ctx, client, store, opts.Owner, opts.Repo); err != nil {
+			return fmt.Errorf("failed to sync repository: %w", err)
+		}
+		state.Repository = &syncTime
+	}
+
+	if opts.Releases {
+		if err := syncReleases(ctx, client, store, opts.Owner, opts.Repo); err != nil {
+			return fmt.Errorf("failed to sync releases: %w", err)
+		}
+		state.Releases = &syncTime
+	}
+
+	if opts.Projects {
+		if err := syncProjects(ctx, client, store, opts.Owner, opts.Repo); err != nil {
+			return fmt.Errorf("failed to sync projects: %w", err)
+		}
+		state.Projects = &syncTime
+	}
+
+	if opts.WorkflowRuns {
+		if err := syncWorkflowRuns(ctx, client, store, opts.Owner, opts.Repo, getSince(state.WorkflowRuns), state); err != nil {
+			return fmt.Errorf("failed to sync workflow runs: %w", err)
+		}
+		state.WorkflowRuns = &syncTime
+	}
+
+	if opts.CommitComments {
+		if err := syncCommitComments(ctx, client, store, opts.Owner, opts.Repo, getSince(state.CommitComments)); err != nil {
+			return fmt.Errorf("failed to sync commit comments: %w", err)
+		}
+		state.CommitComments = &syncTime
+	}
+
 	if err := store.SaveSyncState(state); err != nil {
 		return fmt.Errorf("failed to save sync state: %w", err)
 	}
+	if err := feed.Close(); err != nil {
+		return fmt.Errorf("failed to close change log: %w", err)
+	}
 
+	if opts.Git {
+		if err := commitOutputDir(opts.OutputDir, opts.Owner, opts.Repo, syncTime); err != nil {
+			return fmt.Errorf("failed to commit output directory: %w", err)
+		}
+	}
+
+	return hooks.complete()
+}
+
+// backfillSQLite copies the JSON tree into a database that has never been
+// synced, e.g. one added with --sqlite to an existing output directory.
+// Syncs only fetch what changed since the last one, so the items synced
+// before would otherwise never reach the database.
+func backfillSQLite(db *storage.SQLite, jsonStore *storage.Storage) error {
+	state, err := db.LoadSyncState()
+	if err != nil {
+		return err
+	}
+	if *state != (storage.SyncState{}) {
+		return nil
+	}
+
+	copied, err := db.Backfill(jsonStore)
+	if err != nil {
+		return err
+	}
+	if copied > 0 {
+		fmt.Printf("Copied %d previously synced items into the SQLite database\n", copied)
+	}
 	return nil
 }
 
-func syncIssues(ctx context.Context, client *github.Client, store *storage.Storage, owner, repo string, since *time.Time) error {
+// syncIssues saves the issues updated since the given time. With projects
+// set, their project items are fetched too.
+func syncIssues(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string, since *time.Time, projects bool) error {
 	fmt.Printf("Syncing issues from %s/%s", owner, repo)
 	if since != nil {
 		fmt.Printf(" (since %s)", since.Format(time.RFC3339))
@@ -87,15 +231,30 @@ func syncIssues(ctx context.Context, client *github.Client, store *storage.Stora
 	}
 
 	fmt.Printf("  Found %d issues to sync\n", len(issues))
+	if projects {
+		ids := make([]string, len(issues))
+		for i, issue := range issues {
+			ids[i] = issue.ID
+		}
+		memberships, err := client.FetchProjectMemberships(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to fetch project items: %w", err)
+		}
+		for i := range issues {
+			issues[i].ProjectItems = memberships[issues[i].ID]
+		}
+	}
 	for _, issue := range issues {
-		if err := store.SaveIssue(issue.Number, issue); err != nil {
+		if err := store.Save(storage.KindIssue, issue.Number, issue); err != nil {
 			return fmt.Errorf("failed to save issue %d: %w", issue.Number, err)
 		}
 	}
 	return nil
 }
 
-func syncPRs(ctx context.Context, client *github.Client, store *storage.Storage, owner, repo string, since *time.Time) error {
+// syncPRs saves the pull requests updated since the given time. With
+// projects set, their project items are fetched too.
+func syncPRs(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string, since *time.Time, projects bool) error {
 	fmt.Printf("Syncing pull requests from %s/%s", owner, repo)
 	if since != nil {
 		fmt.Printf(" (since %s)", since.Format(time.RFC3339))
@@ -108,15 +267,28 @@ func syncPRs(ctx context.Context, client *github.Client, store *storage.Storage,
 	}
 
 	fmt.Printf("  Found %d pull requests to sync\n", len(prs))
+	if projects {
+		ids := make([]string, len(prs))
+		for i, pr := range prs {
+			ids[i] = pr.ID
+		}
+		memberships, err := client.FetchProjectMemberships(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("failed to fetch project items: %w", err)
+		}
+		for i := range prs {
+			prs[i].ProjectItems = memberships[prs[i].ID]
+		}
+	}
 	for _, pr := range prs {
-		if err := store.SavePR(pr.Number, pr); err != nil {
+		if err := store.Save(storage.KindPR, pr.Number, pr); err != nil {
 			return fmt.Errorf("failed to save PR %d: %w", pr.Number, err)
 		}
 	}
 	return nil
 }
 
-func syncDiscussions(ctx context.Context, client *github.Client, store *storage.Storage, owner, repo string, since *time.Time) error {
+func syncDiscussions(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string, since *time.Time) error {
 	fmt.Printf("Syncing discussions from %s/%s", owner, repo)
 	if since != nil {
 		fmt.Printf(" (since %s)", since.Format(time.RFC3339))
@@ -130,9 +302,190 @@ func syncDiscussions(ctx context.Context, client *github.Client, store *storage.
 
 	fmt.Printf("  Found %d discussions to sync\n", len(discussions))
 	for _, disc := range discussions {
-		if err := store.SaveDiscussion(disc.Number, disc); err != nil {
+		var prev github.Discussion
+		err := store.Load(storage.KindDiscussion, disc.Number, &prev)
+		if err == nil {
+			mergeDiscussionEvents(&prev, &disc)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to load discussion %d: %w", disc.Number, err)
+		}
+
+		if err := store.Save(storage.KindDiscussion, disc.Number, disc); err != nil {
 			return fmt.Errorf("failed to save discussion %d: %w", disc.Number, err)
 		}
 	}
 	return nil
 }
+
+func syncRepository(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string) error {
+	fmt.Printf("Syncing repository metadata from %s/%s\n", owner, repo)
+
+	repository, err := client.FetchRepository(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Found %d labels and %d milestones\n", len(repository.Labels), len(repository.Milestones))
+	return store.Save(storage.KindRepository, 0, repository)
+}
+
+func syncReleases(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string) error {
+	fmt.Printf("Syncing releases from %s/%s\n", owner, repo)
+
+	releases, err := client.FetchReleases(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Found %d releases to sync\n", len(releases))
+	fetched := map[int]bool{}
+	for _, release := range releases {
+		if err := store.Save(storage.KindRelease, int(release.DatabaseID), release); err != nil {
+			return fmt.Errorf("failed to save release %s: %w", release.TagName, err)
+		}
+		fetched[int(release.DatabaseID)] = true
+	}
+	return deleteMissing(store, storage.KindRelease, fetched)
+}
+
+func syncProjects(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string) error {
+	fmt.Printf("Syncing projects from %s/%s\n", owner, repo)
+
+	projects, err := client.FetchProjects(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Found %d projects to sync\n", len(projects))
+	fetched := map[int]bool{}
+	for _, project := range projects {
+		if err := store.Save(storage.KindProject, project.Number, project); err != nil {
+			return fmt.Errorf("failed to save project %d: %w", project.Number, err)
+		}
+		fetched[project.Number] = true
+	}
+	return deleteMissing(store, storage.KindProject, fetched)
+}
+
+// deleteMissing removes the stored items of a kind that is fetched in full
+// every sync but were not fetched this time, e.g. deleted releases or
+// projects no longer linked to the repository.
+func deleteMissing(store storage.Backend, kind storage.Kind, fetched map[int]bool) error {
+	keys, err := store.List(kind)
+	if err != nil {
+		return err
+	}
+	removed := 0
+	for _, key := range keys {
+		if fetched[key] {
+			continue
+		}
+		if err := store.Delete(kind, key); err != nil {
+			return fmt.Errorf("failed to delete %s %d: %w", kind, key, err)
+		}
+		removed++
+	}
+	if removed > 0 {
+		fmt.Printf("  Removed %d %s items no longer on GitHub\n", removed, kind)
+	}
+	return nil
+}
+
+// syncWorkflowRuns saves the runs created since the given time. Fetching
+// the jobs of every run takes a request each, so each run is saved as soon
+// as it is fetched and the sync state is moved up to its creation time; an
+// interrupted sync resumes from the last saved run.
+func syncWorkflowRuns(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string, since *time.Time, state *storage.SyncState) error {
+	fmt.Printf("Syncing workflow runs from %s/%s", owner, repo)
+	if since != nil {
+		fmt.Printf(" (created since %s)", since.Format(time.RFC3339))
+	}
+	fmt.Println()
+
+	fetched := map[int64]bool{}
+	err := client.FetchWorkflowRuns(ctx, owner, repo, since, func(run github.WorkflowRun) error {
+		if err := store.Save(storage.KindWorkflowRun, int(run.ID), run); err != nil {
+			return fmt.Errorf("failed to save workflow run %d: %w", run.ID, err)
+		}
+		fetched[run.ID] = true
+
+		createdAt := run.CreatedAt
+		state.WorkflowRuns = &createdAt
+		if err := store.SaveSyncState(state); err != nil {
+			return fmt.Errorf("failed to save sync state: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Synced %d workflow runs\n", len(fetched))
+	return refreshWorkflowRuns(ctx, client, store, owner, repo, fetched)
+}
+
+// refreshWorkflowRuns re-fetches the stored runs that had not completed
+// when they were synced. Runs are listed by creation date, so later syncs
+// would not see them again otherwise. Runs deleted since are removed.
+func refreshWorkflowRuns(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string, fetched map[int64]bool) error {
+	keys, err := store.List(storage.KindWorkflowRun)
+	if err != nil {
+		return err
+	}
+
+	var pending []int64
+	for _, key := range keys {
+		if fetched[int64(key)] {
+			continue
+		}
+		var run github.WorkflowRun
+		if err := store.Load(storage.KindWorkflowRun, key, &run); err != nil {
+			return fmt.Errorf("failed to load workflow run %d: %w", key, err)
+		}
+		if run.Status != "completed" {
+			pending = append(pending, run.ID)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	fmt.Printf("  Refreshing %d incomplete workflow runs\n", len(pending))
+	for _, id := range pending {
+		run, err := client.FetchWorkflowRun(ctx, owner, repo, id)
+		if errors.Is(err, github.ErrNotFound) {
+			if err := store.Delete(storage.KindWorkflowRun, int(id)); err != nil {
+				return fmt.Errorf("failed to delete workflow run %d: %w", id, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch workflow run %d: %w", id, err)
+		}
+		if err := store.Save(storage.KindWorkflowRun, int(id), run); err != nil {
+			return fmt.Errorf("failed to save workflow run %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+func syncCommitComments(ctx context.Context, client *github.Client, store storage.Backend, owner, repo string, since *time.Time) error {
+	fmt.Printf("Syncing commit comments from %s/%s", owner, repo)
+	if since != nil {
+		fmt.Printf(" (since %s)", since.Format(time.RFC3339))
+	}
+	fmt.Println()
+
+	comments, err := client.FetchCommitComments(ctx, owner, repo, since)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Found %d commit comments to sync\n", len(comments))
+	for _, comment := range comments {
+		if err := store.Save(storage.KindCommitComment, int(comment.DatabaseID), comment); err != nil {
+			return fmt.Errorf("failed to save commit comment %d: %w", comment.DatabaseID, err)
+		}
+	}
+	return nil
+}
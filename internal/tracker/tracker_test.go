@@ -0,0 +1,47 @@
+package tracker
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestBackfillSQLiteOnlyOnce(t *testing.T) {
+	dir := t.TempDir()
+	jsonStore := storage.New(dir)
+	synced := time.Now()
+	if err := jsonStore.Save(storage.KindIssue, 1, github.Issue{Number: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := jsonStore.SaveSyncState(&storage.SyncState{Issues: &synced}); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := storage.OpenSQLite(filepath.Join(dir, "dump.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := backfillSQLite(db, jsonStore); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := db.List(storage.KindIssue); len(keys) != 1 {
+		t.Fatalf("got issues %v after backfill, want [1]", keys)
+	}
+
+	// Once the database has a sync state, items reach it through syncs
+	// only.
+	if err := jsonStore.Save(storage.KindIssue, 2, github.Issue{Number: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := backfillSQLite(db, jsonStore); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := db.List(storage.KindIssue); len(keys) != 1 {
+		t.Errorf("got issues %v after a second backfill, want [1]", keys)
+	}
+}
@@ -0,0 +1,81 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+// PrintIssueTree writes the sub-issue hierarchy rooted at the given issue,
+// read from the local dump. Sub-issues from other repositories, or ones that
+// have not been synced yet, are printed from the reference on their parent.
+func PrintIssueTree(w io.Writer, outputDir, owner, repo string, number int) error {
+	store := storage.New(outputDir)
+
+	var root github.Issue
+	if err := store.Load(storage.KindIssue, number, &root); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("issue %d not found in %s", number, outputDir)
+		}
+		return fmt.Errorf("failed to load issue %d: %w", number, err)
+	}
+
+	t := issueTree{
+		w:       w,
+		store:   store,
+		repo:    owner + "/" + repo,
+		visited: map[int]bool{},
+	}
+	return t.print(root, 0)
+}
+
+type issueTree struct {
+	w       io.Writer
+	store   storage.Backend
+	repo    string
+	visited map[int]bool
+}
+
+func (t *issueTree) print(issue github.Issue, depth int) error {
+	t.visited[issue.Number] = true
+
+	fmt.Fprintf(t.w, "%s#%d %s [%s]", strings.Repeat("  ", depth), issue.Number, issue.Title, issue.State)
+	if s := issue.SubIssuesSummary; s != nil {
+		fmt.Fprintf(t.w, " (%d/%d completed)", s.Completed, s.Total)
+	}
+	fmt.Fprintln(t.w)
+
+	for _, ref := range issue.SubIssues {
+		if !strings.EqualFold(ref.Repository, t.repo) {
+			fmt.Fprintf(t.w, "%s%s#%d %s [%s]\n", strings.Repeat("  ", depth+1), ref.Repository, ref.Number, ref.Title, ref.State)
+			continue
+		}
+		if t.visited[ref.Number] {
+			t.printRef(ref, depth+1, "cycle")
+			continue
+		}
+
+		var sub github.Issue
+		err := t.store.Load(storage.KindIssue, ref.Number, &sub)
+		if errors.Is(err, fs.ErrNotExist) {
+			t.printRef(ref, depth+1, "not synced")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load issue %d: %w", ref.Number, err)
+		}
+		if err := t.print(sub, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *issueTree) printRef(ref github.ItemRef, depth int, note string) {
+	fmt.Fprintf(t.w, "%s#%d %s [%s] (%s)\n", strings.Repeat("  ", depth), ref.Number, ref.Title, ref.State, note)
+}
@@ -0,0 +1,51 @@
+package tracker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/itaysk/gh-dumpster/internal/github"
+	"github.com/itaysk/gh-dumpster/internal/storage"
+)
+
+func TestPrintIssueTree(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.New(dir)
+	if err := store.EnsureDirs(); err != nil {
+		t.Fatal(err)
+	}
+	ref := func(repo string, number int) github.ItemRef {
+		return github.ItemRef{Repository: repo, Number: number, Title: "ref", State: "OPEN"}
+	}
+	issues := []github.Issue{
+		{
+			Number: 1, Title: "epic", State: "OPEN",
+			SubIssuesSummary: &github.SubIssuesSummary{Total: 3, Completed: 1},
+			SubIssues:        []github.ItemRef{ref("o/r", 2), ref("o/r", 4), ref("other/repo", 5)},
+		},
+		{Number: 2, Title: "task", State: "CLOSED", SubIssues: []github.ItemRef{ref("O/R", 1)}},
+	}
+	for _, issue := range issues {
+		if err := store.Save(storage.KindIssue, issue.Number, issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out strings.Builder
+	if err := PrintIssueTree(&out, dir, "o", "r", 1); err != nil {
+		t.Fatal(err)
+	}
+	want := `#1 epic [OPEN] (1/3 completed)
+  #2 task [CLOSED]
+    #1 ref [OPEN] (cycle)
+  #4 ref [OPEN] (not synced)
+  other/repo#5 ref [OPEN]
+`
+	if out.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	if err := PrintIssueTree(&out, dir, "o", "r", 9); err == nil || !strings.Contains(err.Error(), "issue 9 not found") {
+		t.Errorf("got error %v, want issue 9 not found", err)
+	}
+}